@@ -45,15 +45,23 @@ func main() {
 			Flags: []cli.Flag{},
 			Action: func(c *cli.Context) error {
 				generators := strings.Split(c.App.Metadata["select"].(string), ",")
+				var errs []string
 				if len(generators) == 1 && generators[0] == "" {
 					fmt.Println("🔧 Generating all templates available.")
-					vectra.FullGenerate()
+					if err := vectra.FullGenerate(); err != nil {
+						errs = append(errs, err.Error())
+					}
 				} else {
 					for _, s := range generators {
 						fmt.Println("🔧 Generating", s, "template.")
-						vectra.Generate(s)
+						if err := vectra.Generate(s); err != nil {
+							errs = append(errs, err.Error())
+						}
 					}
 				}
+				if len(errs) > 0 {
+					return cli.NewExitError(strings.Join(errs, "\n"), 1)
+				}
 				return nil
 			},
 		},
@@ -66,6 +74,87 @@ func main() {
 				return nil
 			},
 		},
+		{
+			Name:  "db",
+			Usage: "Manage the database of the application of the project",
+			Subcommands: []cli.Command{
+				{
+					Name:  "migrate",
+					Usage: "Apply the pending migrations of the storage types (with go run)",
+					Action: func(c *cli.Context) error {
+						if err := vectra.Migrate(); err != nil {
+							return cli.NewExitError(err.Error(), 1)
+						}
+						return nil
+					},
+				},
+			},
+		},
+		{
+			Name:  "i18n",
+			Usage: "Manage the translations of the project",
+			Subcommands: []cli.Command{
+				{
+					Name: "check",
+					Usage: "Compare the translations of each language to the default one " +
+						"and check the translations used by the sources (exits with 1 on problems)",
+					Action: func(c *cli.Context) error {
+						problems := vectra.CheckI18n()
+						for _, problem := range problems {
+							fmt.Println(problem)
+						}
+						if len(problems) > 0 {
+							return cli.NewExitError(
+								fmt.Sprintf("%d translation problems found.", len(problems)), 1)
+						}
+						fmt.Println("Translations are complete.")
+						return nil
+					},
+				},
+				{
+					Name:  "export",
+					Usage: "Export the translations of each language to a JSON, YAML or PO file",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "format, f",
+							Value: "po",
+							Usage: "Format of the files: json, yaml or po.",
+						},
+						cli.StringFlag{
+							Name:  "output, o",
+							Value: ".",
+							Usage: "Path to the directory where the files will be written.",
+						},
+					},
+					Action: func(c *cli.Context) error {
+						if err := vectra.ExportI18n(c.String("format"), c.String("output")); err != nil {
+							return cli.NewExitError(err.Error(), 1)
+						}
+						return nil
+					},
+				},
+				{
+					Name:      "import",
+					Usage:     "Import the translations of a JSON, YAML or PO file to the ini files",
+					ArgsUsage: "<file>",
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "lang, l",
+							Usage: "Language of the translations (default: the name of the file).",
+						},
+					},
+					Action: func(c *cli.Context) error {
+						if c.NArg() != 1 {
+							return cli.NewExitError("the file to import is missing", 1)
+						}
+						if err := vectra.ImportI18n(c.Args().First(), c.String("lang")); err != nil {
+							return cli.NewExitError(err.Error(), 1)
+						}
+						return nil
+					},
+				},
+			},
+		},
 		{
 			Name:  "pack",
 			Usage: "Statically build your Vectra-based application and copy all necessary files to the target directory.",
@@ -91,7 +180,7 @@ func main() {
 			Name: "select, s",
 			Usage: "List of generator name separated by comma. " +
 				"Empty value run all generators. (e.g.: services,controllers). " +
-				"Available generators: base, types, services, controllers, " +
+				"Available generators: base, types, services, controllers, openapi, client, " +
 				"i18n (managed by watcher)",
 		},
 	}
@@ -16,12 +16,26 @@ func NewBase(cfg *Vectra) *Generator {
 		NewSourceFile("data/config/configuration.yml.tmpl", Copy),
 		NewSourceFile("static/favicon.ico", Skeleton),
 		NewSourceFile("static/js/main.js", Copy),
+		NewSourceFile("static/js/i18n.js", Copy),
 		NewSourceFile("app.go", CorePart),
+		NewSourceFile("command.go", CorePart),
+		NewSourceFile("migrations/migrations.go", CorePart),
+		NewSourceFile("fixtures/users.yml", Skeleton),
 		NewDynSourceFile("go.mod.embed", "go.mod", CorePart),
 		NewDynSourceFile("go.sum.embed", "go.sum", CorePart),
 		NewSourceFile("src/model/i18n/i18n.go", CorePart),
+		NewSourceFile("src/model/i18n/plural.go", CorePart),
+		NewSourceFile("src/model/i18n/message.go", CorePart),
+		NewSourceFile("src/model/i18n/format.go", CorePart),
+		NewSourceFile("src/model/i18n/watch.go", CorePart),
+		NewSourceFile("src/model/i18n/locale.go", CorePart),
 		NewSourceFile("src/model/service/service.go", CorePart),
+		NewSourceFile("src/model/service/repository.go", CorePart),
 		NewSourceFile("src/model/storage/storage.go", CorePart),
+		NewSourceFile("src/model/storage/index.go", CorePart),
+		NewSourceFile("src/model/storage/migration.go", CorePart),
+		NewSourceFile("src/model/storage/backup.go", CorePart),
+		NewSourceFile("src/model/storage/fixture.go", CorePart),
 		NewSourceFile("src/model/helpers.go", CorePart),
 		NewSourceFile("src/controller/controller.go", CorePart),
 	}
@@ -59,10 +73,12 @@ func NewBase(cfg *Vectra) *Generator {
 			"NetConfDev",
 			"NetConfProd",
 			"DefaultLang",
+			"Roles",
+			"Controllers",
 		},
 		Report{
 			Files:   files,
-			Version: 2,
+			Version: 3,
 		}, cfg)
 
 	n := &Base{}
@@ -74,7 +90,17 @@ func NewBase(cfg *Vectra) *Generator {
 
 func (i *Base) Generate() {
 
-	ctx := map[string]any{"DefaultLang": i.vectra.DefaultLang}
+	rules, err := i.vectra.RouteAccessRules()
+	if err != nil {
+		i.cancel("Invalid access rules", err)
+		return
+	}
+
+	ctx := map[string]any{
+		"DefaultLang": i.vectra.DefaultLang,
+		"Roles":       i.vectra.Roles,
+		"AccessRules": rules,
+	}
 
 	if i.vectra.isProdGen {
 		ctx["Domain"] = i.vectra.NetConfProd.Domain
@@ -0,0 +1,183 @@
+package generator
+
+import (
+	"github.com/serenize/snaker"
+	"slices"
+	"strings"
+)
+
+type Client struct {
+	*Generator
+}
+
+func NewClient(cfg *Vectra) *Generator {
+
+	generator := NewAbstractGenerator(
+		"client",
+		[]string{
+			"Controllers",
+			"Services",
+		},
+		Report{
+			Files: []SourceFile{
+				NewSourceFile("static/js/client.js.tmpl", FullGen),
+			},
+			Version: 1,
+		}, cfg)
+
+	n := &Client{}
+	n.Generator = generator
+	n.IGenerator = n
+
+	return generator
+}
+
+func (i *Client) Generate() {
+
+	if err := i.vectra.resolveMappings(); err != nil {
+		i.cancel("Invalid mappings", err)
+		return
+	}
+
+	i.Generator.Generate(i.vectra.buildClientData())
+}
+
+type ClientData struct {
+	Types       []ClientType
+	Controllers []ClientController
+}
+
+// ClientType is a typedef of the client: an object with properties, or a union of
+// strings for enums.
+type ClientType struct {
+	Name       string
+	Properties []ClientProperty
+	Values     []string
+}
+
+type ClientProperty struct {
+	Name       string
+	Type       string
+	IsOptional bool
+}
+
+type ClientController struct {
+	Name   string
+	Routes []ClientRoute
+}
+
+type ClientRoute struct {
+	Function string
+	Method   string
+	Path     string
+	Params   string
+	Query    string
+	Body     string
+}
+
+// buildClientData prepares the JSDoc types and the functions of the JS client. Enums,
+// value types and exchange types get a typedef. A route gets a typedef for its path
+// parameters and one for its query parameters, when it declares them. Each route of a
+// non-view controller gets a function.
+func (v *Vectra) buildClientData() ClientData {
+
+	var data ClientData
+
+	known := v.describedTypes()
+	for _, enum := range v.Enums {
+		data.Types = append(data.Types, ClientType{Name: enum.Name, Values: enum.Values})
+	}
+	for _, valueType := range v.ValueTypes {
+		data.Types = append(data.Types,
+			newClientType(valueType.Name, withoutTags(valueType.Attributes), known))
+	}
+	for _, service := range v.Services {
+		for _, exchangeType := range service.ExchangeTypes {
+			data.Types = append(data.Types,
+				newClientType(exchangeType.Name, exchangeType.Attributes, known))
+		}
+	}
+
+	for _, controller := range v.Controllers {
+		if controller.IsView {
+			continue
+		}
+
+		c := ClientController{Name: controller.Name}
+		for _, route := range controller.Routes {
+			function := strings.ToLower(controller.Name[:1]) + controller.Name[1:] +
+				Upper(route.Target)
+			r := ClientRoute{
+				Function: function,
+				Method:   strings.ToUpper(route.Kind),
+				Path:     controller.FullPath(route),
+				Body:     route.Body,
+			}
+
+			pathParams := route.PathParams()
+			var params []AttributeWithTag
+			for _, param := range route.Params {
+				if slices.Contains(pathParams, snaker.CamelToSnake(param.Name)) {
+					params = append(params, param)
+				}
+			}
+			if len(params) > 0 {
+				r.Params = Upper(function) + "Params"
+				data.Types = append(data.Types, newClientType(r.Params, params, known))
+			}
+			if len(route.Query) > 0 {
+				r.Query = Upper(function) + "Query"
+				data.Types = append(data.Types, newClientType(r.Query, route.Query, known))
+			}
+
+			c.Routes = append(c.Routes, r)
+		}
+		data.Controllers = append(data.Controllers, c)
+	}
+
+	return data
+}
+
+func newClientType(name string, attributes []AttributeWithTag, known map[string]bool) ClientType {
+
+	t := ClientType{Name: name}
+	for _, attribute := range attributes {
+		t.Properties = append(t.Properties, ClientProperty{
+			Name:       snaker.CamelToSnake(attribute.Name),
+			Type:       jsTypeOf(attribute.Type, known),
+			IsOptional: !slices.Contains(strings.Split(attribute.ValidatorTag, ","), "required"),
+		})
+	}
+
+	return t
+}
+
+// jsTypeOf maps a Go type of an attribute to its JSDoc type.
+func jsTypeOf(goType string, known map[string]bool) string {
+
+	goType = strings.TrimPrefix(goType, "*")
+
+	switch {
+	case goType == "[]byte":
+		return "string"
+	case strings.HasPrefix(goType, "[]"):
+		return "Array<" + jsTypeOf(strings.TrimPrefix(goType, "[]"), known) + ">"
+	case strings.HasPrefix(goType, "map["):
+		valueType := goType[strings.Index(goType, "]")+1:]
+		return "Object<string, " + jsTypeOf(valueType, known) + ">"
+	case known[goType]:
+		return goType
+	}
+
+	switch goType {
+	case "string", "time.Time":
+		return "string"
+	case "bool":
+		return "boolean"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32",
+		"uint64", "float32", "float64":
+		return "number"
+	}
+
+	return "Object"
+}
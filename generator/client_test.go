@@ -0,0 +1,36 @@
+package generator
+
+import "testing"
+
+func TestJsTypeOf(t *testing.T) {
+
+	v := Vectra{
+		Enums:      []Enum{{Name: "Status", Values: []string{"Active"}}},
+		ValueTypes: []VectraType[SimpleAttribute]{{Name: "Address"}},
+		Services: []Service{{ExchangeTypes: []VectraType[AttributeWithTag]{
+			{Name: "UserExch"},
+		}}},
+	}
+	known := v.describedTypes()
+
+	tests := []struct {
+		goType, want string
+	}{
+		{"string", "string"},
+		{"time.Time", "string"},
+		{"[]byte", "string"},
+		{"bool", "boolean"},
+		{"*int64", "number"},
+		{"float32", "number"},
+		{"Status", "Status"},
+		{"[]UserExch", "Array<UserExch>"},
+		{"map[string]Address", "Object<string, Address>"},
+		{"map[string][]int", "Object<string, Array<number>>"},
+		{"User", "Object"},
+	}
+	for _, test := range tests {
+		if got := jsTypeOf(test.goType, known); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.goType, got, test.want)
+		}
+	}
+}
@@ -2,20 +2,106 @@ package generator
 
 import (
 	"fmt"
+	"github.com/serenize/snaker"
+	"slices"
 	"strings"
 )
 
 type Controller struct {
 	Name   string            `yaml:"name"`
+	Prefix string            `yaml:"prefix"`
 	IsView bool              `yaml:"is_view"`
 	Routes []Route           `yaml:"routes"`
 	Bodies map[string]string `yaml:"-"`
 }
 
+// HasRateLimit reports whether a route of the controller is rate limited.
+func (c Controller) HasRateLimit() bool {
+	for _, route := range c.Routes {
+		if route.RateLimit != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// FullPath returns the path of the route as seen by Fiber, prefixed by the group of the
+// controller. It is the component used in access rules.
+func (c Controller) FullPath(route Route) string {
+	path := strings.TrimRight(c.Prefix, "/") + route.Path
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// Route describes an endpoint of a controller. Body is the exchange type read from the
+// request body. Path parameters (`/user/:id`) and query parameters can be declared with
+// Params and Query: a route declaring any of them gets an input struct, named after its
+// target, bound from the parameters, the query string and the Body.
+//
+// Role is the minimum role needed to access the route; it is generated into the access
+// rules of the configuration. The middleware chain of the route is built from
+// RateLimit, CsrfExempt and Middlewares, the names of handlers registered in the app.
 type Route struct {
-	Kind   string `yaml:"kind"`
-	Path   string `yaml:"path"`
-	Target string `yaml:"target"`
+	Kind        string             `yaml:"kind"`
+	Path        string             `yaml:"path"`
+	Target      string             `yaml:"target"`
+	Params      []AttributeWithTag `yaml:"params,omitempty"`
+	Query       []AttributeWithTag `yaml:"query,omitempty"`
+	Body        string             `yaml:"body,omitempty"`
+	Role        string             `yaml:"role"`
+	RateLimit   *RateLimit         `yaml:"rate_limit,omitempty"`
+	CsrfExempt  bool               `yaml:"csrf_exempt,omitempty"`
+	Middlewares []string           `yaml:"middlewares,omitempty"`
+}
+
+// RateLimit allows Max requests by client during Expiration seconds.
+type RateLimit struct {
+	Max        int `yaml:"max"`
+	Expiration int `yaml:"expiration"`
+}
+
+// AccessRule mirrors the access rules read by the generated application.
+type AccessRule struct {
+	Target    string
+	Component string
+	Role      string
+}
+
+// HasInput reports whether an input struct is generated for the route.
+func (r Route) HasInput() bool {
+	return len(r.Params) > 0 || len(r.Query) > 0
+}
+
+// InputName returns the name of the input struct generated for the route.
+func (r Route) InputName() string {
+	return Upper(r.Target) + "Input"
+}
+
+// PathParams returns the names of the parameters found in the path of the route.
+func (r Route) PathParams() []string {
+	var params []string
+	for _, segment := range strings.Split(r.Path, "/") {
+		if strings.HasPrefix(segment, ":") {
+			params = append(params, strings.TrimSuffix(strings.TrimPrefix(segment, ":"), "?"))
+		}
+	}
+	return params
+}
+
+// checkParams returns an error for a declared path parameter which is not in the path:
+// Fiber would never fill it.
+func (r Route) checkParams(controller string) error {
+	pathParams := r.PathParams()
+	for _, param := range r.Params {
+		name := snaker.CamelToSnake(param.Name)
+		if !slices.Contains(pathParams, name) {
+			return fmt.Errorf("the parameter %s of the route %s in controller %s "+
+				"is not in its path", name, r.Path, controller)
+		}
+	}
+	return nil
 }
 
 type Controllers struct {
@@ -46,7 +132,7 @@ func NewControllers(cfg *Vectra) *Generator {
 		},
 		Report{
 			Files:   files,
-			Version: 1,
+			Version: 2,
 		}, cfg)
 
 	n := &Controllers{}
@@ -58,6 +144,20 @@ func NewControllers(cfg *Vectra) *Generator {
 
 func (i *Controllers) Generate() {
 
+	if _, err := i.vectra.RouteAccessRules(); err != nil {
+		i.cancel("Invalid access rules", err)
+		return
+	}
+
+	for _, controller := range i.vectra.Controllers {
+		for _, route := range controller.Routes {
+			if err := route.checkParams(controller.Name); err != nil {
+				i.cancel("Invalid route parameters", err)
+				return
+			}
+		}
+	}
+
 	for n, controller := range i.vectra.Controllers {
 		i.vectra.Controllers[n].Bodies = extractFunctionBody(
 			i.vectra.ProjectPath + "/src/controller/" +
@@ -67,3 +167,43 @@ func (i *Controllers) Generate() {
 
 	i.Generator.Generate(i.vectra.Controllers)
 }
+
+// RouteAccessRules builds the access rules of all routes from their role. A route
+// without role, with an undeclared role or sharing its path with a route requiring
+// another role is an error: the generated application would answer it with a 403.
+func (v *Vectra) RouteAccessRules() ([]AccessRule, error) {
+
+	var rules []AccessRule
+	roleByPath := map[string]string{}
+
+	for _, controller := range v.Controllers {
+		for _, route := range controller.Routes {
+			path := controller.FullPath(route)
+
+			if route.Role == "" {
+				return nil, fmt.Errorf("the route %s %s of controller %s has no role",
+					route.Kind, path, controller.Name)
+			}
+			if _, ok := v.Roles[route.Role]; !ok {
+				return nil, fmt.Errorf("the role %s of route %s %s is not declared",
+					route.Role, route.Kind, path)
+			}
+
+			if role, ok := roleByPath[path]; ok {
+				if role != route.Role {
+					return nil, fmt.Errorf("the path %s requires both %s and %s roles",
+						path, role, route.Role)
+				}
+				continue
+			}
+			roleByPath[path] = route.Role
+			rules = append(rules, AccessRule{
+				Target:    "route",
+				Component: path,
+				Role:      route.Role,
+			})
+		}
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRoutePathParams(t *testing.T) {
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/user", nil},
+		{"/user/:id", []string{"id"}},
+		{"/user/:user_id/post/:post_id?", []string{"user_id", "post_id"}},
+	}
+	for _, test := range tests {
+		got := Route{Path: test.path}.PathParams()
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%s: got %q, want %q", test.path, got, test.want)
+		}
+	}
+}
+
+func TestRouteCheckParams(t *testing.T) {
+
+	param := func(name string) AttributeWithTag {
+		return AttributeWithTag{SimpleAttribute: SimpleAttribute{Name: name, Type: "string"}}
+	}
+
+	tests := []struct {
+		path    string
+		params  []AttributeWithTag
+		wantErr bool
+	}{
+		{"/user", nil, false},
+		{"/user/:id", []AttributeWithTag{param("Id")}, false},
+		{"/user/:user_id/post/:post_id?",
+			[]AttributeWithTag{param("UserId"), param("PostId")}, false},
+		{"/user", []AttributeWithTag{param("Id")}, true},
+		{"/user/:id", []AttributeWithTag{param("UserId")}, true},
+	}
+	for _, test := range tests {
+		err := Route{Path: test.path, Params: test.params}.checkParams("Api")
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error: %t", test.path, err, test.wantErr)
+		}
+	}
+}
+
+func TestRouteAccessRules(t *testing.T) {
+
+	roles := map[string]int{"none": 0, "admin": 2}
+	controller := func(prefix string, routes ...Route) []Controller {
+		return []Controller{{Name: "Api", Prefix: prefix, Routes: routes}}
+	}
+
+	tests := []struct {
+		name        string
+		controllers []Controller
+		want        []AccessRule
+		wantErr     bool
+	}{
+		{"prefixed", controller("/api/", Route{Kind: "Get", Path: "/user", Role: "admin"}),
+			[]AccessRule{{"route", "/api/user", "admin"}}, false},
+		{"root", controller("", Route{Kind: "Get", Path: "", Role: "none"}),
+			[]AccessRule{{"route", "/", "none"}}, false},
+		{"same role", controller("/api",
+			Route{Kind: "Get", Path: "/user", Role: "admin"},
+			Route{Kind: "Post", Path: "/user", Role: "admin"}),
+			[]AccessRule{{"route", "/api/user", "admin"}}, false},
+		{"without role", controller("/api", Route{Kind: "Get", Path: "/user"}), nil, true},
+		{"undeclared role", controller("/api",
+			Route{Kind: "Get", Path: "/user", Role: "owner"}), nil, true},
+		{"other role", controller("/api",
+			Route{Kind: "Get", Path: "/user", Role: "none"},
+			Route{Kind: "Post", Path: "/user", Role: "admin"}), nil, true},
+	}
+	for _, test := range tests {
+		v := Vectra{Roles: roles, Controllers: test.controllers}
+		got, err := v.RouteAccessRules()
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error: %t", test.name, err, test.wantErr)
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%s: got rules %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package generator
+
+import (
+	"fmt"
+	"github.com/go-ini/ini"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Enum is a type with a closed set of values: an int in Go, its value names in JSON,
+// YAML and the generated clients.
+type Enum struct {
+	Name   string   `yaml:"name"`
+	Values []string `yaml:"values"`
+}
+
+// LabelKeys returns the i18n keys, in the enum section, of the labels of the values.
+func (e Enum) LabelKeys() []string {
+	var keys []string
+	for _, value := range e.Values {
+		keys = append(keys, e.Name+value)
+	}
+	return keys
+}
+
+// checkTypes validates the names of enums and value types: they share the storage
+// package with storage types and must not collide. The values of an enum give constants
+// prefixed by its name (e.g. StatusActive) so they must continue a Go identifier.
+func (v *Vectra) checkTypes() error {
+
+	names := map[string]bool{}
+	declare := func(name string) error {
+		if names[name] {
+			return fmt.Errorf("the type %s is declared twice", name)
+		}
+		names[name] = true
+		return nil
+	}
+
+	for _, enum := range v.Enums {
+		if !token.IsIdentifier(enum.Name) {
+			return fmt.Errorf("the enum name %q is not a Go identifier", enum.Name)
+		}
+		if err := declare(enum.Name); err != nil {
+			return err
+		}
+		if len(enum.Values) == 0 {
+			return fmt.Errorf("the enum %s has no value", enum.Name)
+		}
+		for i, value := range enum.Values {
+			if value == "" || !token.IsIdentifier(enum.Name+value) {
+				return fmt.Errorf("the value %q of the enum %s is not valid in a Go identifier",
+					value, enum.Name)
+			}
+			if value == "Names" || value == "Values" {
+				return fmt.Errorf("the value %s of the enum %s collides with %s%s",
+					value, enum.Name, enum.Name, value)
+			}
+			if slices.Contains(enum.Values[:i], value) {
+				return fmt.Errorf("the enum %s declares %s twice", enum.Name, value)
+			}
+		}
+	}
+	for _, valueType := range v.ValueTypes {
+		if err := declare(valueType.Name); err != nil {
+			return err
+		}
+	}
+	for _, storageType := range v.StorageTypes {
+		if err := declare(storageType.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// storedEnums returns the enums used by the attributes of storage types or value types,
+// whose values are stored in the database.
+func (v *Vectra) storedEnums() []Enum {
+
+	var types []string
+	for _, storageType := range v.StorageTypes {
+		for _, attribute := range storageType.Attributes {
+			types = append(types, attribute.Type)
+		}
+	}
+	for _, valueType := range v.ValueTypes {
+		for _, attribute := range valueType.Attributes {
+			types = append(types, attribute.Type)
+		}
+	}
+
+	var enums []Enum
+	for _, enum := range v.Enums {
+		if slices.ContainsFunc(types, func(t string) bool {
+			return slices.Contains(typeNames(t), enum.Name)
+		}) {
+			enums = append(enums, enum)
+		}
+	}
+
+	return enums
+}
+
+// typeNames returns the names of the types composing a Go type, e.g. string and Status
+// for map[string][]Status.
+func typeNames(goType string) []string {
+	return strings.FieldsFunc(goType, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.'
+	})
+}
+
+// describedTypes returns the names of the types described by the OpenAPI schemas and the
+// typedefs of the JS client: enums, value types and exchange types.
+func (v *Vectra) describedTypes() map[string]bool {
+
+	described := map[string]bool{}
+	for _, enum := range v.Enums {
+		described[enum.Name] = true
+	}
+	for _, valueType := range v.ValueTypes {
+		described[valueType.Name] = true
+	}
+	for _, service := range v.Services {
+		for _, exchangeType := range service.ExchangeTypes {
+			described[exchangeType.Name] = true
+		}
+	}
+
+	return described
+}
+
+// writeEnumLabels adds the missing label keys of enum values to the enum.ini file of
+// each language, with the value name as default label.
+func (v *Vectra) writeEnumLabels() error {
+
+	if len(v.Enums) == 0 {
+		return nil
+	}
+
+	root := filepath.Join(v.ProjectPath, "data", "i18n")
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(root, entry.Name(), "enum.ini")
+		cfg, err := ini.LoadSources(
+			ini.LoadOptions{Loose: true, IgnoreInlineComment: true}, path)
+		if err != nil {
+			return err
+		}
+
+		section := cfg.Section("")
+		isEdited := false
+		for _, enum := range v.Enums {
+			for i, key := range enum.LabelKeys() {
+				if !section.HasKey(key) {
+					section.Key(key).SetValue(enum.Values[i])
+					isEdited = true
+				}
+			}
+		}
+
+		if isEdited {
+			if err := cfg.SaveTo(path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
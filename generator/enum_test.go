@@ -0,0 +1,76 @@
+package generator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCheckTypes(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		enums      []Enum
+		valueTypes []string
+		wantErr    bool
+	}{
+		{"valid", []Enum{{Name: "Status", Values: []string{"Active", "Disabled"}}},
+			[]string{"Address"}, false},
+		{"digit value", []Enum{{Name: "Level", Values: []string{"1", "2"}}}, nil, false},
+		{"invalid name", []Enum{{Name: "user-status", Values: []string{"Active"}}}, nil, true},
+		{"no value", []Enum{{Name: "Status"}}, nil, true},
+		{"empty value", []Enum{{Name: "Status", Values: []string{""}}}, nil, true},
+		{"invalid value", []Enum{{Name: "Status", Values: []string{"Not active"}}}, nil, true},
+		{"Names value", []Enum{{Name: "Status", Values: []string{"Names"}}}, nil, true},
+		{"Values value", []Enum{{Name: "Status", Values: []string{"Values"}}}, nil, true},
+		{"duplicated value", []Enum{{Name: "Status", Values: []string{"On", "On"}}}, nil, true},
+		{"duplicated enum", []Enum{
+			{Name: "Status", Values: []string{"On"}},
+			{Name: "Status", Values: []string{"Off"}},
+		}, nil, true},
+		{"enum named as a value type", []Enum{{Name: "Address", Values: []string{"Home"}}},
+			[]string{"Address"}, true},
+		{"enum named as a storage type", []Enum{{Name: "User", Values: []string{"Admin"}}},
+			nil, true},
+	}
+	for _, test := range tests {
+		v := Vectra{
+			Enums:        test.enums,
+			StorageTypes: []VectraType[StorageAttribute]{{Name: "User"}},
+		}
+		for _, name := range test.valueTypes {
+			v.ValueTypes = append(v.ValueTypes, VectraType[SimpleAttribute]{Name: name})
+		}
+		err := v.checkTypes()
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error: %t", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestStoredEnums(t *testing.T) {
+
+	v := Vectra{
+		Enums: []Enum{
+			{Name: "Status", Values: []string{"Active"}},
+			{Name: "Color", Values: []string{"Red"}},
+			{Name: "Theme", Values: []string{"Dark"}},
+			{Name: "Size", Values: []string{"Small"}},
+		},
+		ValueTypes: []VectraType[SimpleAttribute]{{Name: "Preferences", Attributes: []SimpleAttribute{
+			{Name: "Theme", Type: "*Theme"},
+		}}},
+		StorageTypes: []VectraType[StorageAttribute]{{Name: "User", Attributes: []StorageAttribute{
+			{SimpleAttribute: SimpleAttribute{Name: "Status", Type: "Status"}},
+			{SimpleAttribute: SimpleAttribute{Name: "Colors", Type: "map[string][]Color"}},
+			{SimpleAttribute: SimpleAttribute{Name: "Sizes", Type: "[]SizeLabel"}},
+		}}},
+	}
+
+	var got []string
+	for _, enum := range v.storedEnums() {
+		got = append(got, enum.Name)
+	}
+	if want := []string{"Status", "Color", "Theme"}; !slices.Equal(got, want) {
+		t.Errorf("got stored enums %q, want %q", got, want)
+	}
+}
@@ -229,6 +229,13 @@ func (g *Generator) Generate(ctx any) {
 	g.updateReport()
 }
 
+// cancel reports the error which cancels the generation. The vectra command exits with
+// an error status once the selected generators have run.
+func (g *Generator) cancel(reason string, err error) {
+	fmt.Println(reason+":", err, "- generation cancelled.")
+	g.vectra.cancelled = append(g.vectra.cancelled, g.Name)
+}
+
 func (g *Generator) updateReport() {
 
 	for i, file := range g.nextReport.Files {
@@ -362,23 +369,41 @@ func Upper(str string) string {
 	return strings.ToUpper(string(str[0])) + str[1:]
 }
 
+// PluralSuffixes are the suffixes of the keys of the CLDR plural categories, and the
+// former _singular and _plural ones.
+var PluralSuffixes = []string{
+	"_zero", "_one", "_two", "_few", "_many", "_other", "_singular", "_plural",
+}
+
+// IsNotPlural tells if the key is not a plural form.
 func IsNotPlural(str string) bool {
-	return !strings.HasSuffix(str, "_plural")
+	return TrimPluralization(str) == str
 }
 
+// TrimPluralization returns the key of a plural form without its plural suffix.
 func TrimPluralization(str string) string {
-	if len(str) == 0 {
-		return str
+	for _, suffix := range PluralSuffixes {
+		if trimmed, ok := strings.CutSuffix(str, suffix); ok && trimmed != "" {
+			return trimmed
+		}
 	}
+	return str
+}
 
-	if strings.HasSuffix(str, "_plural") {
-		return strings.TrimSuffix(str, "_plural")
-	}
-	if strings.HasSuffix(str, "_singular") {
-		return strings.TrimSuffix(str, "_singular")
+// PluralBase returns the key of a plural form of the dictionary without its plural
+// suffix. A key with a plural suffix is a plural form only when the other form of its
+// key exists (e.g. day_one with day_other), so that step_one and step_two stay keys.
+func PluralBase(key string, dic map[string]string) (string, bool) {
+	base := TrimPluralization(key)
+	if base == key {
+		return key, false
+	}
+	for _, other := range []string{"_other", "_plural"} {
+		if _, ok := dic[base+other]; ok {
+			return base, true
+		}
 	}
-
-	return str
+	return key, false
 }
 
 func TrimNewPrefix(str string) string {
@@ -1,9 +1,11 @@
 package generator
 
 import (
-	"github.com/go-ini/ini"
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -12,20 +14,38 @@ type I18n struct {
 	dic map[string]string
 }
 
+// localePrefix is the prefix of the keys of locale.ini, the patterns of the Formatter
+// of a language (see locale.go of the application).
+const localePrefix = "locale."
+
+// formatterMethods are the methods of the Formatter given by Locale in the Dictionary.
+var formatterMethods = []string{
+	"Date", "Time", "DateTime", "LongDate", "Relative", "Number", "Currency", "Percent",
+}
+
+// JsI18nConfig selects the translations of the JS bundles: the keys under Prefix
+// (js by default, e.g. js.network_error of js.ini).
+type JsI18nConfig struct {
+	Prefix string `yaml:"prefix"`
+}
+
 func NewI18n(cfg *Vectra) *Generator {
 
 	generator := NewAbstractGenerator(
 		"i18n",
 		[]string{
 			"DefaultLang",
+			"Enums",
+			"JsI18nConfig",
 		},
 		Report{
 			Files: []SourceFile{
 				NewSourceFile("src/model/i18n/i18n_gen.go.tmpl", FullGen),
+				NewSourceFile("src/model/storage/langs.go.tmpl", FullGen),
 				NewSourceFile("src/view/pug/shared/i18n_completion_variables.pug.tmpl",
 					FullGen),
 			},
-			Version: 1,
+			Version: 2,
 		}, cfg)
 
 	n := &I18n{}
@@ -37,28 +57,211 @@ func NewI18n(cfg *Vectra) *Generator {
 
 func (i *I18n) Generate() {
 
+	if err := i.vectra.writeEnumLabels(); err != nil {
+		fmt.Println("Failed to write the labels of enums:", err)
+	}
+
+	langs := i.vectra.Langs()
+	if !slices.Contains(langs, i.vectra.DefaultLang) {
+		fmt.Println("The default language", i.vectra.DefaultLang,
+			"has no folder in data/i18n.")
+	}
+
 	i.dic = make(map[string]string)
 
 	path := filepath.Join(i.projectPath, "data", "i18n", i.vectra.DefaultLang)
-	_ = i.loadData(path, "")
+	_ = loadDictionary(path, "", i.dic)
 
-	var root = newFolder("", nil)
+	data, root := i18nTemplateData(i.dic)
 
-	for k := range i.dic {
-		root.add(strings.Split(k, "."))
+	dictionaries, problems := i.vectra.loadDictionaries()
+	for lang, dic := range dictionaries {
+		if lang != i.vectra.DefaultLang {
+			problems = append(problems, comparePlaceholders(lang, dic, i.dic)...)
+		}
+	}
+	for _, problem := range problems {
+		fmt.Println("Translation mismatch:", problem)
 	}
 
-	types := buildDataTemplate(root)
+	if err := i.vectra.writeJsBundles(dictionaries); err != nil {
+		fmt.Println("Failed to write the JS bundles of translations:", err)
+	}
 
 	i.Generator.Generate(map[string]any{
-		"i18n_gen":                  TemplateData{Types: types},
-		"i18n_completion_variables": root,
+		"i18n_gen":                  data,
+		"i18n_completion_variables": map[string]any{"Root": root, "Locale": formatterMethods},
+		"langs":                     i.vectra.langInfos(dictionaries),
 	})
 }
 
+// i18nTemplateData builds the data of the i18n_gen template from the translations of
+// the default language, with the tree of their keys.
+func i18nTemplateData(dic map[string]string) (TemplateData, *Folder) {
+
+	var root = newFolder("", nil)
+
+	// The plural forms of a key (day_one, day_other, ...) give a single function; the
+	// patterns of locale.ini give the Locale formatter instead.
+	for k := range dic {
+		if !strings.HasPrefix(k, localePrefix) {
+			base, _ := PluralBase(k, dic)
+			root.add(strings.Split(base, "."))
+		}
+	}
+
+	types := buildDataTemplate(root)
+
+	// Translations with named placeholders get a typed function.
+	for _, t := range types {
+		for j, field := range t.Fields {
+			if value, ok := dic[field.Key]; ok && !field.IsDirectory {
+				params, err := messagePlaceholders(value)
+				if err != nil {
+					fmt.Println("Invalid translation", field.Key+":", err)
+				}
+				t.Fields[j].Params = params
+			}
+		}
+	}
+
+	var roots []Field
+	for _, f := range root.Items {
+		if len(f.Items) > 0 {
+			roots = append(roots, Field{Name: f.Name, IsDirectory: true, Key: f.FullKey})
+		}
+	}
+	slices.SortFunc(roots, func(a, b Field) int { return strings.Compare(a.Name, b.Name) })
+
+	return TemplateData{Types: types, Roots: roots}, root
+}
+
+// Langs returns the languages of the project: the folders of data/i18n, sorted. The
+// default language is the only one when there are none.
+func (v *Vectra) Langs() []string {
+
+	entries, err := os.ReadDir(filepath.Join(v.ProjectPath, "data", "i18n"))
+	if err != nil {
+		return []string{v.DefaultLang}
+	}
+
+	var langs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			langs = append(langs, entry.Name())
+		}
+	}
+	if len(langs) == 0 {
+		return []string{v.DefaultLang}
+	}
+	slices.Sort(langs)
+
+	return langs
+}
+
+// writeJsBundles writes static/js/i18n/<lang>.js for each language: the translations
+// under the prefix of JsI18nConfig, completed by the ones of the default language, for
+// the t function of i18n.js.
+func (v *Vectra) writeJsBundles(dictionaries map[string]map[string]string) error {
+
+	prefix := v.JsI18nConfig.Prefix
+	if prefix == "" {
+		prefix = defaultVectra.JsI18nConfig.Prefix
+	}
+
+	root := filepath.Join(v.ProjectPath, "static", "js", "i18n")
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return err
+	}
+
+	for _, lang := range v.Langs() {
+		messages := map[string]string{}
+		for _, dic := range []map[string]string{dictionaries[v.DefaultLang], dictionaries[lang]} {
+			for key, value := range dic {
+				if strings.HasPrefix(key, prefix+".") {
+					messages[key] = value
+				}
+			}
+		}
+
+		data, err := json.MarshalIndent(map[string]any{"lang": lang, "messages": messages},
+			"", "    ")
+		if err != nil {
+			return err
+		}
+		content := "// Code generated by Vectra; DO NOT EDIT.\n\n" +
+			"window.i18nBundle = " + string(data) + "\n"
+		if err := os.WriteFile(filepath.Join(root, lang+".js"), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LangInfo describes a language with the metadata of its locale.ini: its name in the
+// language, its text direction and its flag, an SVG of static/svg/flag given by its
+// symbol in the sprite and its viewBox (empty when the SVG is missing).
+type LangInfo struct {
+	Code        string
+	Name        string
+	Dir         string
+	Flag        string
+	FlagViewBox string
+}
+
+// rtlLangs are the languages written from right to left, whose direction is rtl when
+// their locale.ini does not give one.
+var rtlLangs = []string{"ar", "ckb", "dv", "fa", "he", "ps", "sd", "ug", "ur", "yi"}
+
+// langInfos returns the LangInfo of each language. The name defaults to the code of the
+// language and the flag to the SVG named after the language (e.g. flag/fr.svg).
+func (v *Vectra) langInfos(dictionaries map[string]map[string]string) []LangInfo {
+
+	var infos []LangInfo
+	for _, lang := range v.Langs() {
+		dic := dictionaries[lang]
+		base, _, _ := strings.Cut(strings.ToLower(lang), "-")
+
+		info := LangInfo{Code: lang, Name: dic[localePrefix+"name"], Dir: "ltr"}
+		if info.Name == "" {
+			info.Name = lang
+		}
+		if slices.Contains(rtlLangs, base) {
+			info.Dir = "rtl"
+		}
+		switch dir := strings.ToLower(dic[localePrefix+"direction"]); dir {
+		case "ltr", "rtl":
+			info.Dir = dir
+		case "":
+		default:
+			fmt.Println("Unknown direction", dir, "of the language", lang,
+				"(ltr or rtl).")
+		}
+
+		flag := dic[localePrefix+"flag"]
+		if flag == "" {
+			flag = lang
+		}
+		path := filepath.Join(v.ProjectPath, v.SpriteConfig.SvgFolderPath, "flag", flag+".svg")
+		if viewBox, err := svgViewBox(path); err == nil {
+			info.Flag, info.FlagViewBox = "flag-"+flag, viewBox
+		} else if dic[localePrefix+"flag"] != "" {
+			fmt.Println("The flag of the language", lang, "is missing:", err)
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos
+}
+
+// TemplateData describes the generated translation functions: Roots are the top-level
+// folders of the Dictionary of a language.
 type TemplateData struct {
 	PackageName string
 	Types       []I18nType
+	Roots       []Field
 }
 
 type I18nType struct {
@@ -66,10 +269,13 @@ type I18nType struct {
 	Fields []Field
 }
 
+// Field is a folder or a translation of the I18nType of a folder. Params are the named
+// placeholders of the translation in the default language.
 type Field struct {
 	Name        string
 	IsDirectory bool
 	Key         string
+	Params      []Placeholder
 }
 
 type Folder struct {
@@ -79,7 +285,9 @@ type Folder struct {
 	Items   map[string]*Folder
 }
 
-func (i *I18n) loadData(path string, prefix string) error {
+// loadDictionary adds to dic the translations of the files of the folder of a
+// language, by key (the path of the file followed by the name of the entry).
+func loadDictionary(path string, prefix string, dic map[string]string) error {
 
 	entries, err := os.ReadDir(path)
 	if err != nil {
@@ -89,21 +297,24 @@ func (i *I18n) loadData(path string, prefix string) error {
 	for _, entry := range entries {
 		key := entry.Name()
 		if entry.IsDir() {
-			err := i.loadData(filepath.Join(path, key), prefix+key+".")
+			err := loadDictionary(filepath.Join(path, key), prefix+key+".", dic)
 			if err != nil {
 				return err
 			}
-		} else if strings.HasSuffix(key, ".ini") {
-			fullKey := prefix + strings.TrimSuffix(key, ".ini")
+		} else if decode, ok := translationDecoders[filepath.Ext(key)]; ok {
+			fullKey := prefix + strings.TrimSuffix(key, filepath.Ext(key))
 
 			data, err := os.ReadFile(filepath.Join(path, key))
 			if err != nil {
 				return err
 			}
 
-			cfg, _ := ini.LoadSources(ini.LoadOptions{}, data)
-			for _, k := range cfg.Section("").Keys() {
-				i.dic[fullKey+"."+k.Name()] = k.Value()
+			entries, err := decode(data)
+			if err != nil {
+				return fmt.Errorf("%s: %w", filepath.Join(path, key), err)
+			}
+			for k, value := range entries {
+				dic[fullKey+"."+k] = value
 			}
 		}
 	}
@@ -0,0 +1,344 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	formatVerbPattern = regexp.MustCompile(`%[-+# 0]*(\[\d+])?\d*(\.\d+)?[a-zA-Z%]`)
+	i18nCallPattern   = regexp.MustCompile(`\bi18n\.(?:In\([^)]*\)\.)?((?:[A-Z]\w*\.)+[A-Z]\w*)\(`)
+	i18nKeyPattern    = regexp.MustCompile(`\b_i18n\("([^"]+)"`)
+	jsKeyPattern      = regexp.MustCompile(`\bt\(["']([^"']+)["']`)
+
+	// pluralCategories are the CLDR plural categories of integer counts by language (see
+	// plural.go of the application, compared by TestPluralRules); other languages have the
+	// ones of English.
+	pluralCategories = map[string][]string{
+		"en": {"one", "other"},
+		"ar": {"zero", "one", "two", "few", "many", "other"},
+		"cs": {"one", "few", "other"},
+		"cy": {"zero", "one", "two", "few", "many", "other"},
+		"fr": {"one", "other"},
+		"ga": {"one", "two", "few", "many", "other"},
+		"he": {"one", "two", "other"},
+		"hr": {"one", "few", "other"},
+		"ja": {"other"},
+		"ko": {"other"},
+		"lt": {"one", "few", "other"},
+		"lv": {"zero", "one", "other"},
+		"pl": {"one", "few", "many"},
+		"pt": {"one", "other"},
+		"ro": {"one", "few", "other"},
+		"ru": {"one", "few", "many"},
+		"sk": {"one", "few", "other"},
+		"sl": {"one", "two", "few", "other"},
+		"sr": {"one", "few", "other"},
+		"th": {"other"},
+		"uk": {"one", "few", "many"},
+		"vi": {"other"},
+		"zh": {"other"},
+	}
+	pluralSuffixCategories = map[string]string{
+		"_zero": "zero", "_one": "one", "_two": "two", "_few": "few", "_many": "many",
+		"_other": "other", "_singular": "one", "_plural": "other",
+	}
+)
+
+// CheckI18n compares the translations of each language to the ones of the default
+// language and verifies the translations used by the Go and Pug sources of the project.
+// It returns the problems found: missing and extra keys, format verbs which differ from
+// the default language, plural forms missing for the language and unknown references.
+func (v *Vectra) CheckI18n() []string {
+
+	dictionaries, problems := v.loadDictionaries()
+
+	reference, ok := dictionaries[v.DefaultLang]
+	if !ok {
+		return append(problems, fmt.Sprintf("%s: the default language has no translations",
+			v.DefaultLang))
+	}
+
+	for _, lang := range v.Langs() {
+		dic, ok := dictionaries[lang]
+		if !ok {
+			continue
+		}
+		problems = append(problems, checkPluralForms(lang, dic, reference)...)
+		if lang != v.DefaultLang {
+			problems = append(problems, compareDictionary(lang, normalizePlurals(dic, reference),
+				v.DefaultLang, normalizePlurals(reference, reference))...)
+			problems = append(problems, comparePlaceholders(lang, dic, reference)...)
+		}
+	}
+
+	return append(problems, v.checkI18nReferences(reference)...)
+}
+
+// loadDictionaries returns the translations of each language of the project, and the
+// problems met while reading them.
+func (v *Vectra) loadDictionaries() (map[string]map[string]string, []string) {
+
+	var problems []string
+	root := filepath.Join(v.ProjectPath, "data", "i18n")
+
+	dictionaries := map[string]map[string]string{}
+	for _, lang := range v.Langs() {
+		dic := map[string]string{}
+		if err := loadDictionary(filepath.Join(root, lang), "", dic); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", lang, err))
+			continue
+		}
+		dictionaries[lang] = dic
+	}
+
+	return dictionaries, problems
+}
+
+// comparePlaceholders reports the translations whose named placeholders are invalid
+// or differ from the ones of the same key in the reference.
+func comparePlaceholders(lang string, dic map[string]string,
+	reference map[string]string) []string {
+
+	var problems []string
+	for _, key := range sortedKeys(dic) {
+		signature := placeholdersSignature(dic[key])
+		refValue, ok := reference[key]
+		if !ok {
+			continue
+		}
+		if refSignature := placeholdersSignature(refValue); signature != refSignature {
+			problems = append(problems, fmt.Sprintf(
+				"%s: %s has the placeholders [%s] instead of [%s]", lang, key, signature,
+				refSignature))
+		}
+	}
+
+	return problems
+}
+
+// compareDictionary reports the keys missing in or unknown to the language, and its
+// translations whose format verbs differ from the reference.
+func compareDictionary(lang string, dic map[string]string, refLang string,
+	reference map[string]string) []string {
+
+	var problems []string
+
+	for _, key := range sortedKeys(reference) {
+		value, ok := dic[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: missing key %s", lang, key))
+			continue
+		}
+		verbs, refVerbs := formatVerbs(value), formatVerbs(reference[key])
+		if !slices.Equal(verbs, refVerbs) {
+			problems = append(problems, fmt.Sprintf(
+				"%s: %s uses the format verbs [%s] instead of [%s] (%s)", lang, key,
+				strings.Join(verbs, " "), strings.Join(refVerbs, " "), refLang))
+		}
+	}
+
+	for _, key := range sortedKeys(dic) {
+		if _, ok := reference[key]; !ok {
+			problems = append(problems, fmt.Sprintf("%s: extra key %s", lang, key))
+		}
+	}
+
+	return problems
+}
+
+// pluralForm returns the key and the plural category of a plural form, the key being
+// pluralized in the reference dictionary (see PluralBase).
+func pluralForm(key string, reference map[string]string) (string, string, bool) {
+	base, ok := PluralBase(key, reference)
+	if !ok {
+		return key, "", false
+	}
+	return base, pluralSuffixCategories[strings.TrimPrefix(key, base)], true
+}
+
+// normalizePlurals replaces the plural forms of a key by the key, with the translation
+// of its other form (or of its first form) whose format verbs are compared.
+func normalizePlurals(dic map[string]string, reference map[string]string) map[string]string {
+
+	normalized := map[string]string{}
+	for _, key := range sortedKeys(dic) {
+		base, category, ok := pluralForm(key, reference)
+		if !ok {
+			normalized[key] = dic[key]
+		} else if _, found := normalized[base]; !found || category == "other" {
+			normalized[base] = dic[key]
+		}
+	}
+
+	return normalized
+}
+
+// checkPluralForms reports the keys pluralized in the reference dictionary which miss a
+// plural category of the language.
+func checkPluralForms(lang string, dic map[string]string, reference map[string]string) []string {
+
+	categories, ok := pluralCategories[strings.ToLower(strings.SplitN(lang, "-", 2)[0])]
+	if !ok {
+		categories = pluralCategories["en"]
+	}
+
+	forms := map[string]map[string]bool{}
+	for key := range dic {
+		if base, category, ok := pluralForm(key, reference); ok {
+			if forms[base] == nil {
+				forms[base] = map[string]bool{}
+			}
+			forms[base][category] = true
+		}
+	}
+
+	bases := make([]string, 0, len(forms))
+	for base := range forms {
+		bases = append(bases, base)
+	}
+	slices.Sort(bases)
+
+	var problems []string
+	for _, base := range bases {
+		for _, category := range categories {
+			if !forms[base][category] {
+				problems = append(problems,
+					fmt.Sprintf("%s: %s has no %s form", lang, base, category))
+			}
+		}
+	}
+
+	return problems
+}
+
+// checkI18nReferences reports the generated translation functions (i18n.View.Index.Title)
+// and the keys (_i18n("view.index.title"), t("js.network_error")) used in the sources
+// which do not exist in the default language.
+func (v *Vectra) checkI18nReferences(reference map[string]string) []string {
+
+	functions := map[string]bool{}
+	for _, method := range formatterMethods {
+		functions["Locale."+method] = true
+	}
+	for key := range reference {
+		base, _ := PluralBase(key, reference)
+		names := strings.Split(base, ".")
+		for i := range names {
+			names[i] = Upper(names[i])
+		}
+		functions[strings.Join(names, ".")] = true
+	}
+	keyExists := func(key string) bool { return hasKey(reference, key) }
+
+	var problems []string
+	_ = filepath.WalkDir(filepath.Join(v.ProjectPath, "src"),
+		func(path string, entry os.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || entry.Name() == "i18n_gen.go" ||
+				!(strings.HasSuffix(path, ".go") || strings.HasSuffix(path, ".pug")) {
+				return nil
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			rel, _ := filepath.Rel(v.ProjectPath, path)
+
+			for n, line := range strings.Split(string(content), "\n") {
+				for _, match := range i18nCallPattern.FindAllStringSubmatch(line, -1) {
+					if !functions[match[1]] {
+						problems = append(problems,
+							fmt.Sprintf("%s:%d: unknown i18n.%s", rel, n+1, match[1]))
+					}
+				}
+				for _, match := range i18nKeyPattern.FindAllStringSubmatch(line, -1) {
+					if !keyExists(match[1]) {
+						problems = append(problems,
+							fmt.Sprintf("%s:%d: unknown key %s", rel, n+1, match[1]))
+					}
+				}
+			}
+			return nil
+		})
+
+	problems = append(problems, v.checkJsI18nReferences(keyExists)...)
+
+	return problems
+}
+
+// hasKey tells if the key is a translation or a pluralized key of the dictionary.
+func hasKey(dic map[string]string, key string) bool {
+	if _, ok := dic[key]; ok {
+		return true
+	}
+	for _, suffix := range PluralSuffixes {
+		if base, ok := PluralBase(key+suffix, dic); ok && base == key {
+			return true
+		}
+	}
+	return false
+}
+
+// checkJsI18nReferences reports the keys given to t (t("js.network_error")) by the
+// scripts of static/js which do not exist or are not in the JS bundles.
+func (v *Vectra) checkJsI18nReferences(keyExists func(key string) bool) []string {
+
+	prefix := v.JsI18nConfig.Prefix
+	if prefix == "" {
+		prefix = defaultVectra.JsI18nConfig.Prefix
+	}
+
+	scripts, _ := filepath.Glob(filepath.Join(v.ProjectPath, "static", "js", "*.js"))
+
+	var problems []string
+	for _, path := range scripts {
+		if strings.HasPrefix(filepath.Base(path), "prod") {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		rel, _ := filepath.Rel(v.ProjectPath, path)
+
+		for n, line := range strings.Split(string(content), "\n") {
+			for _, match := range jsKeyPattern.FindAllStringSubmatch(line, -1) {
+				if !keyExists(match[1]) {
+					problems = append(problems,
+						fmt.Sprintf("%s:%d: unknown key %s", rel, n+1, match[1]))
+				} else if !strings.HasPrefix(match[1], prefix+".") {
+					problems = append(problems, fmt.Sprintf(
+						"%s:%d: the key %s is not under %s, the prefix of the JS bundles",
+						rel, n+1, match[1], prefix))
+				}
+			}
+		}
+	}
+
+	return problems
+}
+
+// formatVerbs returns the format verbs of a translation, without the escaped %.
+func formatVerbs(value string) []string {
+	var verbs []string
+	for _, verb := range formatVerbPattern.FindAllString(value, -1) {
+		if verb != "%%" {
+			verbs = append(verbs, verb)
+		}
+	}
+	return verbs
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
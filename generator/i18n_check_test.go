@@ -0,0 +1,101 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// i18nRuntimeFiles are the files of the runtime i18n package of the template tested by
+// testdata/i18n_runtime, whose i18n.go and storage package stub the rest of the
+// application.
+var i18nRuntimeFiles = []string{"plural.go", "message.go", "locale.go"}
+
+func TestCheckJsI18nReferences(t *testing.T) {
+
+	v := Vectra{ProjectPath: filepath.Join("testdata", "js_i18n")}
+	reference := map[string]string{
+		"js.network_error": "Network error",
+		"js.items_one":     "{count} item",
+		"js.items_other":   "{count} items",
+		"view.index.title": "Title",
+	}
+	got := v.checkJsI18nReferences(func(key string) bool { return hasKey(reference, key) })
+	want := []string{
+		"static/js/main.js:4: unknown key js.nope",
+		"static/js/main.js:5: the key view.index.title is not under js, the prefix of the JS bundles",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got problems %q, want %q", got, want)
+	}
+}
+
+// testI18nRuntime runs the tests of testdata/i18n_runtime matching the pattern against
+// the runtime i18n package of the template, in a module of its own built by the go
+// command. The pluralCategories of the generator are given to the tests.
+func testI18nRuntime(t *testing.T, pattern string) {
+
+	goCmd, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("the go command is not available")
+	}
+
+	root := t.TempDir()
+	pkg := filepath.Join(root, "src", "model", "i18n")
+	generated := map[string]string{
+		filepath.Join(root, "go.mod"): "module Vectra\n\ngo 1.21\n",
+		filepath.Join(pkg, "plural_categories_test.go"): fmt.Sprintf(
+			"package i18n\n\nvar pluralCategories = %#v\n", pluralCategories),
+	}
+	files := map[string]string{}
+	for _, name := range i18nRuntimeFiles {
+		files[filepath.Join(pkg, name)] = filepath.Join(FolderTemplate, "src", "model", "i18n", name)
+	}
+	stubs, _ := filepath.Glob(filepath.Join("testdata", "i18n_runtime", "*.go"))
+	for _, stub := range stubs {
+		files[filepath.Join(pkg, filepath.Base(stub))] = stub
+	}
+	files[filepath.Join(root, "src", "model", "storage", "storage.go")] =
+		filepath.Join("testdata", "i18n_runtime", "storage", "storage.go")
+
+	for dst, src := range files {
+		data, err := os.ReadFile(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		generated[dst] = string(data)
+	}
+
+	for path, content := range generated {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command(goCmd, "test", "-count=1", "-run", pattern, ".")
+	cmd.Dir = pkg
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off",
+		"GOTOOLCHAIN=local")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("runtime i18n tests failed: %v\n%s", err, out)
+	}
+}
+
+// TestPluralRules checks PluralCategory and that pluralCategories has the languages and
+// the categories of the rules of the runtime (see plural.go).
+func TestPluralRules(t *testing.T) {
+
+	testI18nRuntime(t, "^TestPlural")
+}
+
+// TestFormatter checks the number and date formatting of the runtime (see locale.go).
+func TestFormatter(t *testing.T) {
+
+	testI18nRuntime(t, "^TestFormatter")
+}
@@ -0,0 +1,293 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/go-ini/ini"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// translationDecoders read the translation files of data/i18n by extension. Each entry
+// of a file gets the key of the file (its path in the folder of the language) followed
+// by the name of the entry: nested YAML and JSON objects are joined with dots.
+var translationDecoders = map[string]func(data []byte) (map[string]string, error){
+	".ini":  decodeIni,
+	".yml":  decodeYaml,
+	".yaml": decodeYaml,
+	".json": decodeJson,
+	".po":   decodePo,
+}
+
+// translationEncoders write the translations of a language for vectra i18n export.
+var translationEncoders = map[string]func(lang string, dic map[string]string,
+	reference map[string]string) ([]byte, error){
+	"json": encodeJson,
+	"yaml": encodeYaml,
+	"po":   encodePo,
+}
+
+func decodeIni(data []byte) (map[string]string, error) {
+	cfg, err := ini.LoadSources(ini.LoadOptions{IgnoreInlineComment: true}, data)
+	if err != nil {
+		return nil, err
+	}
+	dic := map[string]string{}
+	for _, k := range cfg.Section("").Keys() {
+		dic[k.Name()] = k.Value()
+	}
+	return dic, nil
+}
+
+func decodeYaml(data []byte) (map[string]string, error) {
+	var values map[string]any
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	dic := map[string]string{}
+	return dic, flattenTranslations("", values, dic)
+}
+
+func decodeJson(data []byte) (map[string]string, error) {
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	dic := map[string]string{}
+	return dic, flattenTranslations("", values, dic)
+}
+
+// flattenTranslations adds the values of nested objects to dic with dotted keys.
+func flattenTranslations(prefix string, values map[string]any, dic map[string]string) error {
+	for key, value := range values {
+		switch v := value.(type) {
+		case map[string]any:
+			if err := flattenTranslations(prefix+key+".", v, dic); err != nil {
+				return err
+			}
+		case []any:
+			return fmt.Errorf("%s%s: a translation could not be a list", prefix, key)
+		case nil:
+			dic[prefix+key] = ""
+		default:
+			dic[prefix+key] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
+
+// nestTranslations is the inverse of flattenTranslations. A key which is also the
+// prefix of other keys stays dotted in its parent.
+func nestTranslations(dic map[string]string) map[string]any {
+	root := map[string]any{}
+	for _, key := range sortedKeys(dic) {
+		node := root
+		names := strings.Split(key, ".")
+		for i, name := range names[:len(names)-1] {
+			child, ok := node[name].(map[string]any)
+			if _, isValue := node[name].(string); isValue {
+				names = append(names[:i], strings.Join(names[i:], "."))
+				break
+			}
+			if !ok {
+				child = map[string]any{}
+				node[name] = child
+			}
+			node = child
+		}
+		node[names[len(names)-1]] = dic[key]
+	}
+	return root
+}
+
+// decodePo reads the entries of a gettext PO file whose msgid are the keys. The header
+// and the untranslated entries are skipped; msgstr[0] is the translation of an entry
+// with plural forms.
+func decodePo(data []byte) (map[string]string, error) {
+
+	dic := map[string]string{}
+	var msgid, msgstr *strings.Builder
+	var current *strings.Builder
+
+	flush := func() {
+		if msgid != nil && msgstr != nil && msgid.Len() > 0 && msgstr.Len() > 0 {
+			dic[msgid.String()] = msgstr.String()
+		}
+		msgid, msgstr, current = nil, nil, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		keyword, rest, _ := strings.Cut(line, " ")
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, `"`):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: string without keyword", n)
+			}
+		case keyword == "msgid":
+			flush()
+			msgid = &strings.Builder{}
+			current, line = msgid, rest
+		case keyword == "msgstr" || keyword == "msgstr[0]":
+			msgstr = &strings.Builder{}
+			current, line = msgstr, rest
+		default:
+			// msgctxt, msgid_plural and the other plural forms are not used.
+			current, line = &strings.Builder{}, rest
+		}
+
+		value, err := strconv.Unquote(strings.TrimSpace(line))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		current.WriteString(value)
+	}
+	flush()
+
+	return dic, scanner.Err()
+}
+
+func encodeJson(_ string, dic map[string]string, _ map[string]string) ([]byte, error) {
+	return json.MarshalIndent(nestTranslations(dic), "", "  ")
+}
+
+func encodeYaml(_ string, dic map[string]string, _ map[string]string) ([]byte, error) {
+	return yaml.Marshal(nestTranslations(dic))
+}
+
+// encodePo writes the translations as a PO file whose msgid are the keys. The keys of
+// the reference missing in the language are written untranslated, and the translation
+// of the reference is given as comment.
+func encodePo(lang string, dic map[string]string, reference map[string]string) ([]byte, error) {
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "msgid \"\"\nmsgstr \"\"\n%s\n%s\n",
+		strconv.Quote("Language: "+lang+"\n"),
+		strconv.Quote("Content-Type: text/plain; charset=UTF-8\n"))
+
+	keys := sortedKeys(dic)
+	for key := range reference {
+		if _, ok := dic[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		b.WriteString("\n")
+		if value, ok := reference[key]; ok && value != dic[key] {
+			fmt.Fprintf(&b, "#. %s\n", strings.ReplaceAll(value, "\n", " "))
+		}
+		fmt.Fprintf(&b, "msgid %s\nmsgstr %s\n", strconv.Quote(key), strconv.Quote(dic[key]))
+	}
+
+	return b.Bytes(), nil
+}
+
+// ExportI18n writes the translations of each language to dir, in a file by language
+// (e.g. fr.po) of the format: json, yaml or po. The PO files of the other languages
+// than the default one give its translations to the translators.
+func (v *Vectra) ExportI18n(format string, dir string) error {
+
+	encode, ok := translationEncoders[format]
+	if !ok {
+		return fmt.Errorf("unknown format %s (json, yaml or po)", format)
+	}
+
+	dictionaries, problems := v.loadDictionaries()
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "\n"))
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for _, lang := range v.Langs() {
+		dic, ok := dictionaries[lang]
+		if !ok {
+			continue
+		}
+		var reference map[string]string
+		if lang != v.DefaultLang {
+			reference = dictionaries[v.DefaultLang]
+		}
+		data, err := encode(lang, dic, reference)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, lang+"."+format)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+		fmt.Println("Translations of", lang, "exported to", path)
+	}
+
+	return nil
+}
+
+// ImportI18n writes the translations of a JSON, YAML or PO file to the ini files of
+// the language (by default the name of the file, e.g. fr.po): the last part of a key is
+// the name of the entry, the previous one the name of its file and the others its
+// folders (view.index.title is title of view/index.ini).
+func (v *Vectra) ImportI18n(path string, lang string) error {
+
+	decode, ok := translationDecoders[filepath.Ext(path)]
+	if !ok {
+		return fmt.Errorf("unknown format of %s (json, yml, yaml or po)", path)
+	}
+	if lang == "" {
+		lang = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	dic, err := decode(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	files := map[string]map[string]string{}
+	for key, value := range dic {
+		names := strings.Split(key, ".")
+		if len(names) < 2 {
+			return fmt.Errorf("the key %s has no file", key)
+		}
+		file := filepath.Join(append([]string{v.ProjectPath, "data", "i18n", lang},
+			names[:len(names)-1]...)...) + ".ini"
+		if files[file] == nil {
+			files[file] = map[string]string{}
+		}
+		files[file][names[len(names)-1]] = value
+	}
+
+	for file, entries := range files {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return err
+		}
+		cfg, err := ini.LoadSources(
+			ini.LoadOptions{Loose: true, IgnoreInlineComment: true}, file)
+		if err != nil {
+			return err
+		}
+		for _, name := range sortedKeys(entries) {
+			cfg.Section("").Key(name).SetValue(entries[name])
+		}
+		if err := cfg.SaveTo(file); err != nil {
+			return err
+		}
+	}
+	fmt.Println(len(dic), "translations imported in", lang)
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package generator
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"maps"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTranslationDecoders(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		ext     string
+		data    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"ini", ".ini", "title = Title ; not a comment\nempty =\n",
+			map[string]string{"title": "Title ; not a comment", "empty": ""}, false},
+		{"yaml", ".yml", "title: Title\npage:\n  count: 3\n  none:\n",
+			map[string]string{"title": "Title", "page.count": "3", "page.none": ""}, false},
+		{"yaml list", ".yaml", "days:\n  - Monday\n", nil, true},
+		{"invalid yaml", ".yml", "title: [", nil, true},
+		{"json", ".json", `{"title": "Title", "page": {"count": 3, "none": null}}`,
+			map[string]string{"title": "Title", "page.count": "3", "page.none": ""}, false},
+		{"json list", ".json", `{"days": ["Monday"]}`, nil, true},
+		{"po", ".po", `# comment
+msgid ""
+msgstr "Content-Type: text/plain; charset=UTF-8\n"
+
+msgid "view.title"
+msgstr "Titre"
+
+msgctxt "menu"
+msgid "view.long"
+msgstr ""
+"Un long "
+"titre"
+
+msgid "view.untranslated"
+msgstr ""
+
+msgid "view.days"
+msgid_plural "view.days"
+msgstr[0] "{count} jour"
+msgstr[1] "{count} jours"
+`, map[string]string{
+			"view.title": "Titre",
+			"view.long":  "Un long titre",
+			"view.days":  "{count} jour",
+		}, false},
+		{"po escapes", ".po", "msgid \"a\"\nmsgstr \"\\\"quoted\\\"\\tand\\nlines\"\n",
+			map[string]string{"a": "\"quoted\"\tand\nlines"}, false},
+		{"po string without keyword", ".po", "\"orphan\"\n", nil, true},
+		{"po unquoted string", ".po", "msgid title\n", nil, true},
+	}
+	for _, test := range tests {
+		got, err := translationDecoders[test.ext]([]byte(test.data))
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error: %t", test.name, err, test.wantErr)
+			continue
+		}
+		if err == nil && !maps.Equal(got, test.want) {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestTranslationEncoders(t *testing.T) {
+
+	dic := map[string]string{
+		"view.title":       "Titre",
+		"view.index":       "Accueil",
+		"view.index.quote": "\"Bienvenue\"\n",
+	}
+	decoders := map[string]string{"json": ".json", "yaml": ".yml", "po": ".po"}
+
+	for format, encode := range translationEncoders {
+		data, err := encode("fr", dic, dic)
+		if err != nil {
+			t.Errorf("%s: got error %v", format, err)
+			continue
+		}
+		got, err := translationDecoders[decoders[format]](data)
+		if err != nil {
+			t.Errorf("%s: got error %v decoding\n%s", format, err, data)
+			continue
+		}
+		if !maps.Equal(got, dic) {
+			t.Errorf("%s: got %q after a round trip, want %q", format, got, dic)
+		}
+	}
+}
+
+// TestRuntimeDecoders checks that the decoders of the application (format.go of the
+// runtime i18n package) are the ones tested here.
+func TestRuntimeDecoders(t *testing.T) {
+
+	decls := func(path string) map[string]string {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		funcs := map[string]string{}
+		for _, decl := range file.Decls {
+			if f, ok := decl.(*ast.FuncDecl); ok {
+				buf := new(bytes.Buffer)
+				_ = printer.Fprint(buf, fset, f.Body)
+				funcs[f.Name.Name] = strings.ReplaceAll(buf.String(), "flattenTranslations(",
+					"flatten(")
+			}
+		}
+		return funcs
+	}
+
+	generator := decls("i18n_format.go")
+	runtime := decls(filepath.Join(FolderTemplate, "src", "model", "i18n", "format.go"))
+	generator["flatten"] = generator["flattenTranslations"]
+
+	for _, name := range []string{"decodeIni", "decodeYaml", "decodeJson", "flatten", "decodePo"} {
+		if runtime[name] == "" || runtime[name] != generator[name] {
+			t.Errorf("%s of the runtime differs from the one of the generator", name)
+		}
+	}
+}
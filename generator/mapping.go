@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+)
+
+// resolveMappings completes the view and exchange types declaring `from` with the
+// attributes of their storage type: the included ones (all by default) minus the
+// excluded and sensitive ones. A declared attribute with the name of a mapped one keeps
+// its tags, and its type is checked at generation time: it must be the type of the
+// mapped attribute. MappedFields is filled with the mapped attributes, copied by the
+// generated conversion functions (e.g. UserCtxFromUser and ApplyUserExch).
+func (v *Vectra) resolveMappings() error {
+
+	for i := range v.ViewTypes.Types {
+		err := resolveMapping(&v.ViewTypes.Types[i], v.StorageTypes,
+			func(a SimpleAttribute) SimpleAttribute { return a },
+			func(a SimpleAttribute) SimpleAttribute { return a })
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range v.Services {
+		for j := range v.Services[i].ExchangeTypes {
+			err := resolveMapping(&v.Services[i].ExchangeTypes[j], v.StorageTypes,
+				func(a AttributeWithTag) SimpleAttribute { return a.SimpleAttribute },
+				func(a SimpleAttribute) AttributeWithTag {
+					return AttributeWithTag{SimpleAttribute: a}
+				})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func resolveMapping[T any](
+	t *VectraType[T],
+	storageTypes []VectraType[StorageAttribute],
+	simple func(T) SimpleAttribute,
+	wrap func(SimpleAttribute) T,
+) error {
+
+	t.MappedFields = nil
+	if t.From == "" {
+		return nil
+	}
+
+	i := slices.IndexFunc(storageTypes, func(s VectraType[StorageAttribute]) bool {
+		return s.Name == t.From
+	})
+	if i == -1 {
+		return fmt.Errorf("%s is derived from %s which is not a storage type", t.Name, t.From)
+	}
+	source := storageTypes[i]
+
+	for _, name := range append(slices.Clone(t.Include), t.Exclude...) {
+		j := slices.IndexFunc(source.Attributes, func(a StorageAttribute) bool {
+			return a.Name == name
+		})
+		if j == -1 {
+			return fmt.Errorf("%s selects %s which is not an attribute of %s",
+				t.Name, name, t.From)
+		}
+		if slices.Contains(t.Include, name) && source.Attributes[j].IsSensitive {
+			return fmt.Errorf("%s includes %s.%s which is sensitive", t.Name, t.From, name)
+		}
+	}
+
+	for _, attribute := range source.Attributes {
+		if len(t.Include) > 0 && !slices.Contains(t.Include, attribute.Name) ||
+			slices.Contains(t.Exclude, attribute.Name) || attribute.IsSensitive {
+			continue
+		}
+
+		j := slices.IndexFunc(t.Attributes, func(a T) bool {
+			return simple(a).Name == attribute.Name
+		})
+		if j == -1 {
+			t.Attributes = append(t.Attributes, wrap(attribute.SimpleAttribute))
+		} else if declared := simple(t.Attributes[j]); declared.Type != attribute.Type {
+			return fmt.Errorf("%s.%s has the type %s while %s.%s has the type %s",
+				t.Name, declared.Name, declared.Type, t.From, attribute.Name, attribute.Type)
+		}
+		t.MappedFields = append(t.MappedFields, attribute.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestResolveMapping(t *testing.T) {
+
+	user := VectraType[StorageAttribute]{Name: "User", Attributes: []StorageAttribute{
+		{SimpleAttribute: SimpleAttribute{Name: "Email", Type: "string"}},
+		{SimpleAttribute: SimpleAttribute{Name: "Password", Type: "[]byte", IsSensitive: true}},
+		{SimpleAttribute: SimpleAttribute{Name: "Age", Type: "int"}},
+	}}
+
+	tests := []struct {
+		name       string
+		mapped     VectraType[SimpleAttribute]
+		wantFields []string
+		wantTypes  []string
+		wantErr    bool
+	}{
+		{"no mapping", VectraType[SimpleAttribute]{Name: "UserCtx"}, nil, nil, false},
+		{"all but sensitive", VectraType[SimpleAttribute]{Name: "UserCtx", From: "User"},
+			[]string{"Email", "Age"}, []string{"string", "int"}, false},
+		{"included", VectraType[SimpleAttribute]{Name: "UserCtx", From: "User",
+			Include: []string{"Age"}}, []string{"Age"}, []string{"int"}, false},
+		{"excluded", VectraType[SimpleAttribute]{Name: "UserCtx", From: "User",
+			Exclude: []string{"Age"}}, []string{"Email"}, []string{"string"}, false},
+		{"declared attribute kept", VectraType[SimpleAttribute]{Name: "UserCtx", From: "User",
+			Attributes: []SimpleAttribute{{Name: "ID", Type: "string"}, {Name: "Age", Type: "int"}}},
+			[]string{"Email", "Age"}, []string{"string", "int", "string"}, false},
+		{"declared type mismatch", VectraType[SimpleAttribute]{Name: "UserCtx", From: "User",
+			Attributes: []SimpleAttribute{{Name: "Age", Type: "string"}}}, nil, nil, true},
+		{"unknown source", VectraType[SimpleAttribute]{Name: "UserCtx", From: "Account"},
+			nil, nil, true},
+		{"unknown included", VectraType[SimpleAttribute]{Name: "UserCtx", From: "User",
+			Include: []string{"Name"}}, nil, nil, true},
+		{"unknown excluded", VectraType[SimpleAttribute]{Name: "UserCtx", From: "User",
+			Exclude: []string{"Name"}}, nil, nil, true},
+		{"sensitive included", VectraType[SimpleAttribute]{Name: "UserCtx", From: "User",
+			Include: []string{"Password"}}, nil, nil, true},
+	}
+	for _, test := range tests {
+		mapped := test.mapped
+		err := resolveMapping(&mapped, []VectraType[StorageAttribute]{user},
+			func(a SimpleAttribute) SimpleAttribute { return a },
+			func(a SimpleAttribute) SimpleAttribute { return a })
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error: %t", test.name, err, test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !slices.Equal(mapped.MappedFields, test.wantFields) {
+			t.Errorf("%s: got mapped fields %v, want %v", test.name, mapped.MappedFields,
+				test.wantFields)
+		}
+		var types []string
+		for _, a := range mapped.Attributes {
+			types = append(types, a.Type)
+		}
+		if !slices.Equal(types, test.wantTypes) {
+			t.Errorf("%s: got attribute types %v, want %v", test.name, types, test.wantTypes)
+		}
+	}
+}
+
+func TestResolveMappings(t *testing.T) {
+
+	v := Vectra{
+		StorageTypes: []VectraType[StorageAttribute]{{Name: "User", Attributes: []StorageAttribute{
+			{SimpleAttribute: SimpleAttribute{Name: "Email", Type: "string"}},
+			{SimpleAttribute: SimpleAttribute{Name: "Password", Type: "[]byte", IsSensitive: true}},
+		}}},
+		ViewTypes: ViewTypes{Types: []VectraType[SimpleAttribute]{{Name: "UserCtx", From: "User"}}},
+		Services: []Service{{Name: "Api", ExchangeTypes: []VectraType[AttributeWithTag]{{
+			Name: "UserExch", From: "User",
+			Attributes: []AttributeWithTag{{
+				SimpleAttribute: SimpleAttribute{Name: "Email", Type: "string"},
+				ValidatorTag:    "required,email",
+			}},
+		}}}},
+	}
+	if err := v.resolveMappings(); err != nil {
+		t.Fatalf("got error %v", err)
+	}
+
+	if got := v.ViewTypes.Types[0].MappedFields; !slices.Equal(got, []string{"Email"}) {
+		t.Errorf("view type: got mapped fields %v, want [Email]", got)
+	}
+	exch := v.Services[0].ExchangeTypes[0]
+	if len(exch.Attributes) != 1 || exch.Attributes[0].ValidatorTag != "required,email" {
+		t.Errorf("exchange type: got attributes %+v, want the declared Email", exch.Attributes)
+	}
+
+	v.Services[0].ExchangeTypes[0].Attributes[0].Type = "int"
+	if err := v.resolveMappings(); err == nil {
+		t.Errorf("exchange type: got no error for a declared type mismatch")
+	}
+}
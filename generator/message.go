@@ -0,0 +1,196 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// Placeholder is a named argument of an ICU-style translation: {name} (any value),
+// {name, plural, ...} (an int) or {name, select, ...} (a string).
+type Placeholder struct {
+	Name   string
+	GoName string
+	Type   string
+}
+
+// messagePlaceholders returns the placeholders of a translation in their order of
+// appearance, with the type of their first use as plural or select.
+func messagePlaceholders(message string) ([]Placeholder, error) {
+
+	p := placeholderParser{src: message}
+	p.message()
+	if p.err == nil && p.pos < len(p.src) {
+		p.err = fmt.Errorf("unexpected } at %d", p.pos)
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	// The Go parameters are prefixed so that a placeholder could not shadow the names
+	// used by the generated function (e.g. {format}, {any} or {lang}).
+	for i := range p.placeholders {
+		name := p.placeholders[i].Name
+		if token.IsIdentifier(name) {
+			p.placeholders[i].GoName = "p_" + name
+		} else {
+			p.placeholders[i].GoName = fmt.Sprintf("p%d", i)
+		}
+	}
+
+	return p.placeholders, nil
+}
+
+// placeholdersSignature describes the placeholders of a translation, regardless of
+// their order, to compare the translations of a key between languages.
+func placeholdersSignature(message string) string {
+	placeholders, err := messagePlaceholders(message)
+	if err != nil {
+		return "invalid message: " + err.Error()
+	}
+	var names []string
+	for _, p := range placeholders {
+		names = append(names, "{"+p.Name+" "+p.Type+"}")
+	}
+	slices.Sort(names)
+	return strings.Join(names, " ")
+}
+
+type placeholderParser struct {
+	src          string
+	pos          int
+	err          error
+	placeholders []Placeholder
+}
+
+// message reads a (sub-)message up to its closing brace or the end.
+func (p *placeholderParser) message() {
+	for p.err == nil && p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '}':
+			return
+		case '{':
+			p.pos++
+			p.argument()
+		case '\'':
+			p.quoted()
+		default:
+			p.pos++
+		}
+	}
+}
+
+// quoted skips an apostrophe: two apostrophes are an apostrophe and a quote starting
+// with a brace or # is literal text up to the next apostrophe.
+func (p *placeholderParser) quoted() {
+	p.pos++
+	if p.pos >= len(p.src) {
+		return
+	}
+	if p.src[p.pos] == '\'' {
+		p.pos++
+		return
+	}
+	if !strings.ContainsRune("{}#", rune(p.src[p.pos])) {
+		return
+	}
+	end := strings.IndexByte(p.src[p.pos:], '\'')
+	if end == -1 {
+		p.pos = len(p.src)
+		return
+	}
+	p.pos += end + 1
+}
+
+// argument reads an argument after its opening brace.
+func (p *placeholderParser) argument() {
+
+	name := strings.TrimSpace(p.until(",}"))
+	if name == "" {
+		p.err = fmt.Errorf("placeholder without name at %d", p.pos)
+		return
+	}
+	if p.pos >= len(p.src) {
+		p.err = fmt.Errorf("placeholder %s is not closed", name)
+		return
+	}
+
+	kind := ""
+	if p.src[p.pos] == ',' {
+		p.pos++
+		kind = strings.TrimSpace(p.until(",}"))
+		if kind != "plural" && kind != "select" {
+			p.err = fmt.Errorf("placeholder %s has the unknown type %s", name, kind)
+			return
+		}
+		if p.pos >= len(p.src) || p.src[p.pos] != ',' {
+			p.err = fmt.Errorf("placeholder %s has no options", name)
+			return
+		}
+		p.pos++
+	}
+	p.add(name, kind)
+	if kind == "" {
+		p.pos++
+		return
+	}
+
+	// Options: a selector followed by a sub-message in braces.
+	for p.err == nil {
+		p.skipSpaces()
+		if p.pos >= len(p.src) {
+			p.err = fmt.Errorf("placeholder %s is not closed", name)
+			return
+		}
+		if p.src[p.pos] == '}' {
+			p.pos++
+			return
+		}
+		selector := strings.TrimSpace(p.until("{}"))
+		if selector == "" || p.pos >= len(p.src) || p.src[p.pos] != '{' {
+			p.err = fmt.Errorf("placeholder %s has an option without message", name)
+			return
+		}
+		p.pos++
+		p.message()
+		if p.pos >= len(p.src) {
+			p.err = fmt.Errorf("the option %s of %s is not closed", selector, name)
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *placeholderParser) add(name string, kind string) {
+	typ := "any"
+	switch kind {
+	case "plural":
+		typ = "int"
+	case "select":
+		typ = "string"
+	}
+
+	i := slices.IndexFunc(p.placeholders, func(placeholder Placeholder) bool {
+		return placeholder.Name == name
+	})
+	if i == -1 {
+		p.placeholders = append(p.placeholders, Placeholder{Name: name, Type: typ})
+	} else if typ != "any" {
+		p.placeholders[i].Type = typ
+	}
+}
+
+func (p *placeholderParser) until(chars string) string {
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune(chars, rune(p.src[p.pos])) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *placeholderParser) skipSpaces() {
+	for p.pos < len(p.src) && strings.ContainsRune(" \t\n", rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+)
+
+// i18nStubs declare what the generated i18n package uses from the rest of the runtime
+// i18n package and from the storage package.
+const (
+	i18nStub = `package i18n
+
+type I18n struct{}
+
+func GetInstance() *I18n                                                 { return nil }
+func (i *I18n) GetIn(lang string, key string, args ...interface{}) string { return "" }
+func (i *I18n) FormatIn(lang string, key string, args map[string]any) string {
+	return ""
+}
+
+type Formatter struct{}
+
+func FormatterIn(lang string) Formatter { return Formatter{} }
+`
+	storageStub = `package storage
+
+var DefaultLang = "en"
+
+func IsLang(lang string) bool { return true }
+`
+)
+
+// stubImporter imports the storage stub and the standard library.
+type stubImporter struct {
+	fset    *token.FileSet
+	storage *types.Package
+}
+
+func (i *stubImporter) Import(path string) (*types.Package, error) {
+	if path != "Vectra/src/model/storage" {
+		return importer.Default().Import(path)
+	}
+	if i.storage == nil {
+		file, err := parser.ParseFile(i.fset, "storage.go", storageStub, 0)
+		if err != nil {
+			return nil, err
+		}
+		conf := types.Config{Importer: importer.Default()}
+		i.storage, err = conf.Check(path, i.fset, []*ast.File{file}, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return i.storage, nil
+}
+
+func TestMessagePlaceholdersGoNames(t *testing.T) {
+
+	placeholders, err := messagePlaceholders(
+		"{format} {any} {string} {lang} {map} {call} {count, plural, other {#}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"p_format", "p_any", "p_string", "p_lang", "p4", "p_call", "p_count"}
+	if len(placeholders) != len(want) {
+		t.Fatalf("got %d placeholders, want %d", len(placeholders), len(want))
+	}
+	for i, p := range placeholders {
+		if p.GoName != want[i] {
+			t.Errorf("placeholder %s: got Go name %s, want %s", p.Name, p.GoName, want[i])
+		}
+	}
+}
+
+// TestI18nGenPlaceholderNames checks that the i18n package generated for placeholders
+// named after the identifiers of its functions compiles.
+func TestI18nGenPlaceholderNames(t *testing.T) {
+
+	data, _ := i18nTemplateData(map[string]string{
+		"view.export":  "Export as {format}",
+		"view.convert": "{any} to {string} in {lang}",
+		"view.items":   "{count, plural, one {# item of {map}} other {# items of {map}}}",
+		"view.gender":  "{call, select, male {He} female {She} other {They}}",
+		"view.title":   "Title",
+	})
+
+	in, err := os.ReadFile(filepath.Join(FolderTemplate, "src", "model", "i18n", "i18n_gen.go.tmpl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := template.New("i18n_gen").Funcs(template.FuncMap{"Upper": Upper}).Parse(string(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	if err := parsed.Execute(buf, map[string]any{"i18n_gen": data}); err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range map[string]string{"i18n_gen.go": buf.String(), "i18n.go": i18nStub} {
+		file, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			t.Fatalf("%v\n%s", err, src)
+		}
+		files = append(files, file)
+	}
+
+	conf := types.Config{Importer: &stubImporter{fset: fset}}
+	if _, err := conf.Check("Vectra/src/model/i18n", fset, files, nil); err != nil {
+		t.Fatalf("the generated i18n package does not compile: %v\n%s", err, buf)
+	}
+}
@@ -0,0 +1,212 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/serenize/snaker"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+var (
+	FileSchema       = filepath.Join(FolderProject, "schema.yml")
+	FolderMigrations = "migrations"
+)
+
+// StorageSchema is the snapshot of the storage types, and of the enums of their values,
+// for which the last migration was generated. Its version is recorded in the database of
+// the application.
+type StorageSchema struct {
+	Version int                            `yaml:"version"`
+	Types   []VectraType[StorageAttribute] `yaml:"types"`
+	Enums   []Enum                         `yaml:"enums,omitempty"`
+}
+
+type migrationData struct {
+	Version  int
+	Name     string
+	Changes  []string
+	Previous []VectraType[StorageAttribute]
+}
+
+// updateSchema compares the storage types with the last schema snapshot of the project.
+// When existing records could not be decoded as is anymore (a type or an attribute
+// removed, an attribute with another type), when the stored values of an enum change of
+// meaning or when an attribute becomes unique, the version is incremented and a
+// migration skeleton is written in the migrations folder. The returned schema is the
+// one to generate.
+func (v *Vectra) updateSchema() (StorageSchema, error) {
+
+	path := filepath.Join(v.ProjectPath, FileSchema)
+	next := StorageSchema{Version: 1, Types: v.StorageTypes, Enums: v.storedEnums()}
+
+	var previous StorageSchema
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if err := yaml.Unmarshal(data, &previous); err != nil {
+			return previous, fmt.Errorf("invalid schema snapshot %s: %w", path, err)
+		}
+		next.Version = previous.Version
+
+		changes := append(diffStorageTypes(previous.Types, next.Types),
+			diffEnums(previous.Enums, next.Enums)...)
+		if len(changes) > 0 {
+			next.Version++
+
+			var changed []VectraType[StorageAttribute]
+			for _, t := range previous.Types {
+				if slices.ContainsFunc(changes, func(change string) bool {
+					name, _, _ := strings.Cut(change, ":")
+					return strings.HasPrefix(change, t.Name+".") || name == t.Name ||
+						slices.ContainsFunc(t.Attributes, func(a StorageAttribute) bool {
+							return slices.Contains(typeNames(a.Type), name)
+						})
+				}) {
+					changed = append(changed, t)
+				}
+			}
+
+			err := v.writeMigration(migrationData{
+				Version:  next.Version,
+				Name:     migrationName(changes),
+				Changes:  changes,
+				Previous: changed,
+			})
+			if err != nil {
+				return previous, err
+			}
+		}
+	}
+
+	data, err = yaml.Marshal(next)
+	if err != nil {
+		return previous, err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return previous, err
+	}
+
+	return next, nil
+}
+
+// diffStorageTypes describes the changes breaking the decoding of records stored with
+// the previous types, and the attributes becoming unique whose stored values may have
+// duplicates to resolve. Added types and attributes are handled by gob and are ignored,
+// as the other changes of indexes, rebuilt at startup (see CheckIndexes).
+func diffStorageTypes(previous []VectraType[StorageAttribute],
+	next []VectraType[StorageAttribute]) []string {
+
+	var changes []string
+	for _, p := range previous {
+		i := slices.IndexFunc(next, func(t VectraType[StorageAttribute]) bool {
+			return t.Name == p.Name
+		})
+		if i == -1 {
+			changes = append(changes, p.Name+": removed")
+			continue
+		}
+		for _, attribute := range p.Attributes {
+			j := slices.IndexFunc(next[i].Attributes, func(a StorageAttribute) bool {
+				return a.Name == attribute.Name
+			})
+			if j == -1 {
+				changes = append(changes, p.Name+"."+attribute.Name+": removed")
+			} else if next[i].Attributes[j].Type != attribute.Type {
+				changes = append(changes, fmt.Sprintf("%s.%s: type %s became %s",
+					p.Name, attribute.Name, attribute.Type, next[i].Attributes[j].Type))
+			} else if next[i].Attributes[j].IsUnique && !attribute.IsUnique {
+				changes = append(changes, p.Name+"."+attribute.Name+": became unique")
+			}
+		}
+	}
+
+	return changes
+}
+
+// diffEnums describes the enums whose stored values change of meaning. A value is stored
+// as its position in the enum, so only the values added after the previous ones keep the
+// meaning of stored records. An enum no longer stored is ignored: the attributes using
+// it are changed or removed.
+func diffEnums(previous []Enum, next []Enum) []string {
+
+	var changes []string
+	for _, p := range previous {
+		i := slices.IndexFunc(next, func(e Enum) bool { return e.Name == p.Name })
+		if i == -1 {
+			continue
+		}
+		values := next[i].Values
+		if len(values) < len(p.Values) || !slices.Equal(values[:len(p.Values)], p.Values) {
+			changes = append(changes, fmt.Sprintf("%s: values %s became %s", p.Name,
+				strings.Join(p.Values, ", "), strings.Join(values, ", ")))
+		}
+	}
+
+	return changes
+}
+
+// migrationName names the migration after the changed types (e.g. update_user).
+func migrationName(changes []string) string {
+
+	var names []string
+	for _, change := range changes {
+		name, _, _ := strings.Cut(change, ":")
+		name, _, _ = strings.Cut(name, ".")
+		name = snaker.CamelToSnake(name)
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	return "update_" + strings.Join(names, "_")
+}
+
+func (v *Vectra) writeMigration(data migrationData) error {
+
+	path := filepath.Join(v.ProjectPath, FolderMigrations,
+		fmt.Sprintf("%04d_%s.go", data.Version, data.Name))
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	in, err := EmbedFS.ReadFile(filepath.Join(FolderTemplate, "migrations", "migration.go.tmpl"))
+	if err != nil {
+		return err
+	}
+	parsed, err := template.New("migration").Parse(string(in))
+	if err != nil {
+		return err
+	}
+
+	buf := new(bytes.Buffer)
+	if err := parsed.Execute(buf, data); err != nil {
+		return err
+	}
+	content := buf.Bytes()
+	if formatGoCode(buf) == nil {
+		content = buf.Bytes()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0744); err != nil {
+		return err
+	}
+	fmt.Println("Storage types changed: migration skeleton written at", path)
+
+	return os.WriteFile(path, content, 0644)
+}
+
+// Migrate applies, with the migrate command of the application, the pending migrations
+// to the database of the project.
+func (v *Vectra) Migrate() error {
+	err := ExecuteCommand("cd "+strconv.Quote(v.ProjectPath)+" && go run . db migrate",
+		true, true)
+	if err != nil {
+		return fmt.Errorf("failed to migrate the database: %w", err)
+	}
+	return nil
+}
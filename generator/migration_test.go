@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiffStorageTypes(t *testing.T) {
+
+	attribute := func(name, typ string, isUnique bool) StorageAttribute {
+		return StorageAttribute{SimpleAttribute: SimpleAttribute{Name: name, Type: typ},
+			IsUnique: isUnique}
+	}
+	user := func(attributes ...StorageAttribute) []VectraType[StorageAttribute] {
+		return []VectraType[StorageAttribute]{{Name: "User", Attributes: attributes}}
+	}
+
+	tests := []struct {
+		name           string
+		previous, next []VectraType[StorageAttribute]
+		want           []string
+	}{
+		{"same", user(attribute("Email", "string", false)),
+			user(attribute("Email", "string", false)), nil},
+		{"added attribute", user(attribute("Email", "string", false)),
+			user(attribute("Email", "string", false), attribute("Age", "int", false)), nil},
+		{"added type", nil, user(attribute("Email", "string", false)), nil},
+		{"no longer unique", user(attribute("Email", "string", true)),
+			user(attribute("Email", "string", false)), nil},
+		{"removed type", user(attribute("Email", "string", false)), nil,
+			[]string{"User: removed"}},
+		{"removed attribute", user(attribute("Email", "string", false)), user(),
+			[]string{"User.Email: removed"}},
+		{"changed type", user(attribute("Age", "int", false)),
+			user(attribute("Age", "string", false)),
+			[]string{"User.Age: type int became string"}},
+		{"became unique", user(attribute("Email", "string", false)),
+			user(attribute("Email", "string", true)),
+			[]string{"User.Email: became unique"}},
+	}
+	for _, test := range tests {
+		got := diffStorageTypes(test.previous, test.next)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%s: got changes %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestMigrationName(t *testing.T) {
+
+	tests := []struct {
+		changes []string
+		want    string
+	}{
+		{[]string{"User.Email: removed"}, "update_user"},
+		{[]string{"User.Email: removed", "User.Age: type int became string"}, "update_user"},
+		{[]string{"SessionItem: removed", "User.Lang: removed"}, "update_session_item_user"},
+	}
+	for _, test := range tests {
+		if got := migrationName(test.changes); got != test.want {
+			t.Errorf("%q: got %s, want %s", test.changes, got, test.want)
+		}
+	}
+}
+
+func TestDiffEnums(t *testing.T) {
+
+	status := func(values ...string) []Enum {
+		return []Enum{{Name: "Status", Values: values}}
+	}
+
+	tests := []struct {
+		name           string
+		previous, next []Enum
+		want           []string
+	}{
+		{"same", status("Active", "Disabled"), status("Active", "Disabled"), nil},
+		{"added at the end", status("Active"), status("Active", "Disabled"), nil},
+		{"no longer stored", status("Active"), nil, nil},
+		{"newly stored", nil, status("Active"), nil},
+		{"reordered", status("Active", "Disabled"), status("Disabled", "Active"),
+			[]string{"Status: values Active, Disabled became Disabled, Active"}},
+		{"inserted", status("Active", "Disabled"), status("Active", "Pending", "Disabled"),
+			[]string{"Status: values Active, Disabled became Active, Pending, Disabled"}},
+		{"removed", status("Active", "Disabled"), status("Active"),
+			[]string{"Status: values Active, Disabled became Active"}},
+	}
+	for _, test := range tests {
+		got := diffEnums(test.previous, test.next)
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%s: got changes %q, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,446 @@
+package generator
+
+import (
+	"fmt"
+	"github.com/serenize/snaker"
+	"gopkg.in/yaml.v3"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type OpenApiConfig struct {
+	Version string `yaml:"version"`
+}
+
+type OpenApi struct {
+	*Generator
+}
+
+func NewOpenApi(cfg *Vectra) *Generator {
+
+	generator := NewAbstractGenerator(
+		"openapi",
+		[]string{
+			"ProjectName",
+			"OpenApiConfig",
+			"Controllers",
+			"Services",
+		},
+		Report{
+			Files: []SourceFile{
+				NewSourceFile("openapi.yaml.tmpl", FullGen),
+			},
+			Version: 1,
+		}, cfg)
+
+	n := &OpenApi{}
+	n.Generator = generator
+	n.IGenerator = n
+
+	return generator
+}
+
+func (i *OpenApi) Generate() {
+
+	if err := i.vectra.resolveMappings(); err != nil {
+		i.cancel("Invalid mappings", err)
+		return
+	}
+
+	data, err := yaml.Marshal(i.vectra.buildOpenApiDoc())
+	if err != nil {
+		fmt.Println("Failed to build the OpenAPI specification:", err)
+		return
+	}
+
+	i.Generator.Generate(string(data))
+}
+
+//region Specification types
+
+type openApiDoc struct {
+	OpenApi    string                                  `yaml:"openapi"`
+	Info       openApiInfo                             `yaml:"info"`
+	Paths      map[string]map[string]*openApiOperation `yaml:"paths"`
+	Components openApiComponents                       `yaml:"components"`
+}
+
+type openApiInfo struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+type openApiComponents struct {
+	Schemas         map[string]*openApiSchema         `yaml:"schemas,omitempty"`
+	Responses       map[string]*openApiResponse       `yaml:"responses"`
+	SecuritySchemes map[string]*openApiSecurityScheme `yaml:"securitySchemes"`
+}
+
+type openApiOperation struct {
+	OperationId string                      `yaml:"operationId"`
+	Tags        []string                    `yaml:"tags"`
+	Parameters  []*openApiParameter         `yaml:"parameters,omitempty"`
+	RequestBody *openApiRequestBody         `yaml:"requestBody,omitempty"`
+	Responses   map[string]*openApiResponse `yaml:"responses"`
+	Security    []map[string][]string       `yaml:"security,omitempty"`
+}
+
+type openApiParameter struct {
+	Name     string         `yaml:"name"`
+	In       string         `yaml:"in"`
+	Required bool           `yaml:"required"`
+	Schema   *openApiSchema `yaml:"schema"`
+}
+
+type openApiRequestBody struct {
+	Required bool                     `yaml:"required"`
+	Content  map[string]*openApiMedia `yaml:"content"`
+}
+
+type openApiResponse struct {
+	Ref         string                   `yaml:"$ref,omitempty"`
+	Description string                   `yaml:"description,omitempty"`
+	Content     map[string]*openApiMedia `yaml:"content,omitempty"`
+}
+
+type openApiMedia struct {
+	Schema *openApiSchema `yaml:"schema"`
+}
+
+type openApiSecurityScheme struct {
+	Type        string `yaml:"type"`
+	In          string `yaml:"in"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+}
+
+type openApiSchema struct {
+	Ref                  string                    `yaml:"$ref,omitempty"`
+	Type                 string                    `yaml:"type,omitempty"`
+	Format               string                    `yaml:"format,omitempty"`
+	WriteOnly            bool                      `yaml:"writeOnly,omitempty"`
+	Items                *openApiSchema            `yaml:"items,omitempty"`
+	AdditionalProperties *openApiSchema            `yaml:"additionalProperties,omitempty"`
+	Properties           map[string]*openApiSchema `yaml:"properties,omitempty"`
+	Required             []string                  `yaml:"required,omitempty"`
+	Enum                 []string                  `yaml:"enum,omitempty"`
+	MinLength            *int                      `yaml:"minLength,omitempty"`
+	MaxLength            *int                      `yaml:"maxLength,omitempty"`
+	Minimum              *float64                  `yaml:"minimum,omitempty"`
+	Maximum              *float64                  `yaml:"maximum,omitempty"`
+	MinItems             *int                      `yaml:"minItems,omitempty"`
+	MaxItems             *int                      `yaml:"maxItems,omitempty"`
+}
+
+//endregion
+
+// buildOpenApiDoc describes the routes of all non-view controllers. Request and response
+// bodies refer to the exchange types of services, and the validator tags of attributes
+// are translated into schema constraints.
+func (v *Vectra) buildOpenApiDoc() openApiDoc {
+
+	doc := openApiDoc{
+		OpenApi: "3.0.3",
+		Info:    openApiInfo{Title: v.ProjectName, Version: v.OpenApiConfig.Version},
+		Paths:   map[string]map[string]*openApiOperation{},
+		Components: openApiComponents{
+			Schemas: map[string]*openApiSchema{},
+			Responses: map[string]*openApiResponse{
+				"Forbidden": {Description: "The role of the user is not sufficient."},
+				"TooManyRequests": {
+					Description: "The rate limit of the route is reached."},
+			},
+			SecuritySchemes: map[string]*openApiSecurityScheme{
+				"csrf": {
+					Type:        "apiKey",
+					In:          "header",
+					Name:        "X-CSRF-Token",
+					Description: "The value of the csrf-token cookie.",
+				},
+				"session": {
+					Type:        "apiKey",
+					In:          "cookie",
+					Name:        "session-id",
+					Description: "The session of a connected user.",
+				},
+			},
+		},
+	}
+
+	known := v.describedTypes()
+	for _, enum := range v.Enums {
+		doc.Components.Schemas[enum.Name] = &openApiSchema{Type: "string", Enum: enum.Values}
+	}
+	for _, valueType := range v.ValueTypes {
+		doc.Components.Schemas[valueType.Name] = objectSchema(
+			withoutTags(valueType.Attributes), known)
+	}
+	for _, service := range v.Services {
+		for _, exchangeType := range service.ExchangeTypes {
+			doc.Components.Schemas[exchangeType.Name] = objectSchema(
+				exchangeType.Attributes, known)
+		}
+	}
+
+	reason := &openApiSchema{Type: "object"}
+	if known["ReasonExch"] {
+		reason = &openApiSchema{Ref: "#/components/schemas/ReasonExch"}
+	}
+	doc.Components.Responses["BadRequest"] = &openApiResponse{
+		Description: "The request or its data is invalid, or the service rejected it.",
+		Content:     map[string]*openApiMedia{"application/json": {Schema: reason}},
+	}
+
+	minLevel := 0
+	for _, level := range v.Roles {
+		minLevel = min(minLevel, level)
+	}
+
+	for _, controller := range v.Controllers {
+		if controller.IsView {
+			continue
+		}
+
+		var serviceErrors []string
+		for _, service := range v.Services {
+			if service.Name == controller.Name {
+				serviceErrors = service.Errors
+			}
+		}
+
+		for _, route := range controller.Routes {
+			op := &openApiOperation{
+				OperationId: controller.Name + Upper(route.Target),
+				Tags:        []string{controller.Name},
+				Responses: map[string]*openApiResponse{
+					"200": {
+						Description: "The request succeeded.",
+						Content: map[string]*openApiMedia{
+							"application/json": {Schema: reason}},
+					},
+					"400": {Ref: "#/components/responses/BadRequest"},
+					"403": {Ref: "#/components/responses/Forbidden"},
+				},
+			}
+			if len(serviceErrors) > 0 {
+				op.Responses["400"] = &openApiResponse{
+					Description: "The request or its data is invalid, or the service " +
+						"rejected it with one of: " + strings.Join(serviceErrors, ", ") + ".",
+					Content: map[string]*openApiMedia{"application/json": {Schema: reason}},
+				}
+			}
+			if route.RateLimit != nil {
+				op.Responses["429"] = &openApiResponse{
+					Ref: "#/components/responses/TooManyRequests"}
+			}
+
+			pathParams := route.PathParams()
+			for _, param := range route.Params {
+				// Parameters missing from the path are never filled by Fiber.
+				if !slices.Contains(pathParams, snaker.CamelToSnake(param.Name)) {
+					continue
+				}
+				op.Parameters = append(op.Parameters,
+					newOpenApiParameter(param, "path", known))
+			}
+			for _, param := range route.Query {
+				op.Parameters = append(op.Parameters,
+					newOpenApiParameter(param, "query", known))
+			}
+
+			if route.Body != "" {
+				op.RequestBody = &openApiRequestBody{
+					Required: true,
+					Content: map[string]*openApiMedia{
+						"application/json": {Schema: schemaOfType(route.Body, known)},
+					},
+				}
+			}
+
+			// Both schemes are required together, so they share the same requirement.
+			requirement := map[string][]string{}
+			method := strings.ToLower(route.Kind)
+			if !route.CsrfExempt && method != "get" && method != "head" {
+				requirement["csrf"] = []string{}
+			}
+			if level, ok := v.Roles[route.Role]; ok && level > minLevel {
+				requirement["session"] = []string{}
+			}
+			if len(requirement) > 0 {
+				op.Security = []map[string][]string{requirement}
+			}
+
+			path := openApiPath(controller.FullPath(route))
+			if doc.Paths[path] == nil {
+				doc.Paths[path] = map[string]*openApiOperation{}
+			}
+			doc.Paths[path][method] = op
+		}
+	}
+
+	return doc
+}
+
+// openApiPath converts the Fiber parameters of a path (`:id`) in OpenAPI ones (`{id}`).
+func openApiPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + strings.TrimSuffix(segment[1:], "?") + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func newOpenApiParameter(
+	attribute AttributeWithTag,
+	in string,
+	known map[string]bool,
+) *openApiParameter {
+
+	schema := schemaOfType(attribute.Type, known)
+	required := applyValidatorTag(schema, attribute.ValidatorTag)
+
+	return &openApiParameter{
+		Name:     snaker.CamelToSnake(attribute.Name),
+		In:       in,
+		Required: required || in == "path",
+		Schema:   schema,
+	}
+}
+
+func objectSchema(attributes []AttributeWithTag, known map[string]bool) *openApiSchema {
+
+	schema := &openApiSchema{Type: "object", Properties: map[string]*openApiSchema{}}
+
+	for _, attribute := range attributes {
+		name := snaker.CamelToSnake(attribute.Name)
+		property := schemaOfType(attribute.Type, known)
+		if attribute.IsSensitive {
+			property.Format = "password"
+			property.WriteOnly = true
+		}
+		if applyValidatorTag(property, attribute.ValidatorTag) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+
+	return schema
+}
+
+// schemaOfType maps a Go type of an attribute to its schema. Exchange types are
+// referenced; other named types are described as free objects.
+func schemaOfType(goType string, known map[string]bool) *openApiSchema {
+
+	goType = strings.TrimPrefix(goType, "*")
+
+	switch {
+	case goType == "[]byte":
+		return &openApiSchema{Type: "string", Format: "byte"}
+	case strings.HasPrefix(goType, "[]"):
+		return &openApiSchema{Type: "array",
+			Items: schemaOfType(strings.TrimPrefix(goType, "[]"), known)}
+	case strings.HasPrefix(goType, "map["):
+		valueType := goType[strings.Index(goType, "]")+1:]
+		return &openApiSchema{Type: "object",
+			AdditionalProperties: schemaOfType(valueType, known)}
+	case known[goType]:
+		return &openApiSchema{Ref: "#/components/schemas/" + goType}
+	}
+
+	switch goType {
+	case "string":
+		return &openApiSchema{Type: "string"}
+	case "bool":
+		return &openApiSchema{Type: "boolean"}
+	case "int", "int8", "int16", "int32", "uint", "uint8", "uint16", "uint32":
+		return &openApiSchema{Type: "integer", Format: "int32"}
+	case "int64", "uint64":
+		return &openApiSchema{Type: "integer", Format: "int64"}
+	case "float32":
+		return &openApiSchema{Type: "number", Format: "float"}
+	case "float64":
+		return &openApiSchema{Type: "number", Format: "double"}
+	case "time.Time":
+		return &openApiSchema{Type: "string", Format: "date-time"}
+	}
+
+	return &openApiSchema{Type: "object"}
+}
+
+// applyValidatorTag translates the validator tags into constraints of the schema and
+// reports whether the value is required.
+func applyValidatorTag(schema *openApiSchema, tag string) bool {
+
+	required := false
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		switch name {
+		case "required":
+			required = true
+		case "email":
+			schema.Format = "email"
+		case "url", "uri":
+			schema.Format = "uri"
+		case "uuid", "uuid4":
+			schema.Format = "uuid"
+		case "oneof":
+			schema.Enum = strings.Fields(param)
+		case "min", "gte":
+			schema.setBound(param, true)
+		case "max", "lte":
+			schema.setBound(param, false)
+		case "len":
+			schema.setBound(param, true)
+			schema.setBound(param, false)
+		}
+	}
+
+	return required
+}
+
+// setBound sets the lower or upper bound of the schema following its type: length for
+// strings, items for arrays and value for numbers.
+func (s *openApiSchema) setBound(param string, isLower bool) {
+
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+	count := int(value)
+
+	switch s.Type {
+	case "string":
+		if isLower {
+			s.MinLength = &count
+		} else {
+			s.MaxLength = &count
+		}
+	case "array":
+		if isLower {
+			s.MinItems = &count
+		} else {
+			s.MaxItems = &count
+		}
+	case "integer", "number":
+		if isLower {
+			s.Minimum = &value
+		} else {
+			s.Maximum = &value
+		}
+	}
+}
+
+// withoutTags turns the attributes of a value type into attributes without mod and
+// validator tags.
+func withoutTags(attributes []SimpleAttribute) []AttributeWithTag {
+	var result []AttributeWithTag
+	for _, attribute := range attributes {
+		result = append(result, AttributeWithTag{SimpleAttribute: attribute})
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"gopkg.in/yaml.v3"
+	"testing"
+)
+
+func TestOpenApiPath(t *testing.T) {
+
+	tests := []struct {
+		path, want string
+	}{
+		{"/user", "/user"},
+		{"/user/:id", "/user/{id}"},
+		{"/user/:user_id/post/:post_id?", "/user/{user_id}/post/{post_id}"},
+		{"/", "/"},
+	}
+	for _, test := range tests {
+		if got := openApiPath(test.path); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.path, got, test.want)
+		}
+	}
+}
+
+func TestApplyValidatorTag(t *testing.T) {
+
+	tests := []struct {
+		schemaType   string
+		tag          string
+		wantRequired bool
+		want         string
+	}{
+		{"string", "", false, "type: string\n"},
+		{"string", "required,email", true, "type: string\nformat: email\n"},
+		{"string", "url", false, "type: string\nformat: uri\n"},
+		{"string", "omitempty,uuid4", false, "type: string\nformat: uuid\n"},
+		{"string", "oneof=red green", false, "type: string\nenum:\n    - red\n    - green\n"},
+		{"string", "required, min=3, max=20", true,
+			"type: string\nminLength: 3\nmaxLength: 20\n"},
+		{"string", "len=6", false, "type: string\nminLength: 6\nmaxLength: 6\n"},
+		{"integer", "gte=1,lte=10", false, "type: integer\nminimum: 1\nmaximum: 10\n"},
+		{"array", "min=1", false, "type: array\nminItems: 1\n"},
+		{"string", "startswith=a", false, "type: string\n"},
+	}
+	for _, test := range tests {
+		schema := &openApiSchema{Type: test.schemaType}
+		required := applyValidatorTag(schema, test.tag)
+		if required != test.wantRequired {
+			t.Errorf("%q: got required %t, want %t", test.tag, required, test.wantRequired)
+		}
+		data, _ := yaml.Marshal(schema)
+		if string(data) != test.want {
+			t.Errorf("%q: got schema\n%s\nwant\n%s", test.tag, data, test.want)
+		}
+	}
+}
+
+func TestSetBound(t *testing.T) {
+
+	tests := []struct {
+		schemaType string
+		param      string
+		isLower    bool
+		want       string
+	}{
+		{"string", "3", true, "type: string\nminLength: 3\n"},
+		{"string", "8", false, "type: string\nmaxLength: 8\n"},
+		{"array", "2", true, "type: array\nminItems: 2\n"},
+		{"array", "5", false, "type: array\nmaxItems: 5\n"},
+		{"number", "0.5", true, "type: number\nminimum: 0.5\n"},
+		{"integer", "100", false, "type: integer\nmaximum: 100\n"},
+		{"boolean", "1", true, "type: boolean\n"},
+		{"string", "abc", true, "type: string\n"},
+	}
+	for _, test := range tests {
+		schema := &openApiSchema{Type: test.schemaType}
+		schema.setBound(test.param, test.isLower)
+		data, _ := yaml.Marshal(schema)
+		if string(data) != test.want {
+			t.Errorf("%s %s: got schema\n%s\nwant\n%s", test.schemaType, test.param, data,
+				test.want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// pageCtxType is the type given as ctx to the Pug templates (see the constructors with
+// is_page_ctx).
+const pageCtxType = "GlobalCtx"
+
+var pugCtxPattern = regexp.MustCompile(`\bctx((?:\.[A-Za-z_]\w*)+)(\()?`)
+
+// CompletionVariable is a field of the page context described for the completion of Pug
+// editors: a nested object for the declared types, a placeholder value otherwise.
+// Indent is the indentation of the line closing the nested object.
+type CompletionVariable struct {
+	Name   string
+	Value  string
+	Indent string
+	Fields []CompletionVariable
+}
+
+// CompletionFunction is a view type constructor callable from the Pug templates.
+type CompletionFunction struct {
+	Name       string
+	Parameters []string
+	Result     CompletionVariable
+}
+
+// viewFields returns the attributes of the types reachable from the page context, by
+// type name, and the names of the enums (ints without attributes).
+func (v *Vectra) viewFields() (map[string][]SimpleAttribute, map[string]bool) {
+
+	fields := map[string][]SimpleAttribute{}
+	for _, t := range v.StorageTypes {
+		for _, a := range t.Attributes {
+			fields[t.Name] = append(fields[t.Name], a.SimpleAttribute)
+		}
+	}
+	for _, t := range append(slices.Clone(v.ValueTypes), v.ViewTypes.Types...) {
+		fields[t.Name] = slices.Clone(t.Attributes)
+	}
+
+	enums := map[string]bool{}
+	for _, e := range v.Enums {
+		enums[e.Name] = true
+	}
+
+	return fields, enums
+}
+
+// completionOf describes a value of the given type, expanding the declared types which
+// are not already being expanded.
+func completionOf(name string, typ string, indent string,
+	fields map[string][]SimpleAttribute, enums map[string]bool,
+	expanding []string) CompletionVariable {
+
+	variable := CompletionVariable{Name: name, Indent: indent}
+	typ = strings.TrimPrefix(typ, "*")
+
+	switch {
+	case typ == "bool":
+		variable.Value = "false"
+	case typ == "string":
+		variable.Value = `""`
+	case enums[typ], strings.HasPrefix(typ, "int"), strings.HasPrefix(typ, "uint"),
+		strings.HasPrefix(typ, "float"), typ == "byte", typ == "rune":
+		variable.Value = "0"
+	case strings.HasPrefix(typ, "[]"):
+		variable.Value = "[]"
+	case strings.HasPrefix(typ, "map["):
+		variable.Value = "{}"
+	default:
+		attributes, ok := fields[typ]
+		if !ok || slices.Contains(expanding, typ) {
+			variable.Value = "null"
+			break
+		}
+		for _, a := range attributes {
+			variable.Fields = append(variable.Fields,
+				completionOf(a.Name, a.Type, indent+"    ", fields, enums,
+					append(expanding, typ)))
+		}
+	}
+
+	return variable
+}
+
+// pugCompletion returns the page context and the constructors of the view types for the
+// completion variables of the Pug templates.
+func (v *Vectra) pugCompletion() (CompletionVariable, []CompletionFunction) {
+
+	fields, enums := v.viewFields()
+	ctx := completionOf("ctx", pageCtxType, "    ", fields, enums, nil)
+
+	var functions []CompletionFunction
+	for _, c := range v.ViewTypes.Constructors {
+		result := TrimNewPrefix(c.Name)
+		if c.IsPageCtx {
+			result = pageCtxType
+		}
+		function := CompletionFunction{
+			Name:   c.Name,
+			Result: completionOf("", result, "        ", fields, enums, nil),
+		}
+		for _, a := range c.Attributes {
+			function.Parameters = append(function.Parameters, a.Name)
+		}
+		functions = append(functions, function)
+	}
+
+	return ctx, functions
+}
+
+// checkPugVariables verifies that the fields of ctx referenced in the Pug templates of
+// the project exist on the page context type. The fields of types not declared in the
+// project (e.g. time.Time) are not checked; a trailing method call is ignored. The
+// returned error lists every unknown field.
+func (v *Vectra) checkPugVariables() error {
+
+	fields, enums := v.viewFields()
+	root := filepath.Join(v.ProjectPath, "src", "view", "pug")
+
+	var problems []error
+	_ = filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !strings.HasSuffix(path, ".pug") ||
+			strings.Contains(entry.Name(), "completion_variables") {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(v.ProjectPath, path)
+
+		for n, line := range strings.Split(string(content), "\n") {
+			for _, match := range pugCtxPattern.FindAllStringSubmatch(line, -1) {
+				names := strings.Split(strings.TrimPrefix(match[1], "."), ".")
+				if match[2] != "" {
+					names = names[:len(names)-1]
+				}
+				if err := checkFieldPath(pageCtxType, names, fields, enums); err != nil {
+					problems = append(problems,
+						fmt.Errorf("%s:%d: ctx%s: %w", rel, n+1, match[1], err))
+				}
+			}
+		}
+		return nil
+	})
+
+	return errors.Join(problems...)
+}
+
+// checkFieldPath follows the field names from the type.
+func checkFieldPath(typ string, names []string, fields map[string][]SimpleAttribute,
+	enums map[string]bool) error {
+
+	for _, name := range names {
+		typ = strings.TrimPrefix(typ, "*")
+		attributes, ok := fields[typ]
+		if !ok {
+			if enums[typ] || !strings.Contains(typ, ".") {
+				return fmt.Errorf("%s has no field %s", typ, name)
+			}
+			return nil
+		}
+
+		i := slices.IndexFunc(attributes, func(a SimpleAttribute) bool {
+			return a.Name == name
+		})
+		if i == -1 {
+			return fmt.Errorf("%s has no field %s", typ, name)
+		}
+		typ = attributes[i].Type
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var pugTestFields = map[string][]SimpleAttribute{
+	"GlobalCtx": {
+		{Name: "Lang", Type: "string"},
+		{Name: "User", Type: "*UserCtx"},
+		{Name: "Created", Type: "time.Time"},
+	},
+	"UserCtx": {
+		{Name: "Email", Type: "string"},
+		{Name: "Status", Type: "Status"},
+		{Name: "Manager", Type: "*UserCtx"},
+		{Name: "Tags", Type: "[]string"},
+	},
+}
+
+var pugTestEnums = map[string]bool{"Status": true}
+
+func TestCheckFieldPath(t *testing.T) {
+
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{"Lang", false},
+		{"User.Email", false},
+		{"User.Manager.Email", false},
+		{"Created.Year", false},
+		{"Missing", true},
+		{"User.Missing", true},
+		{"User.Status.Name", true},
+		{"Lang.Length", true},
+	}
+	for _, test := range tests {
+		err := checkFieldPath("GlobalCtx", strings.Split(test.path, "."), pugTestFields,
+			pugTestEnums)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error: %t", test.path, err, test.wantErr)
+		}
+	}
+}
+
+func TestCompletionOf(t *testing.T) {
+
+	tests := []struct {
+		typ        string
+		wantValue  string
+		wantFields []string
+	}{
+		{"bool", "false", nil},
+		{"string", `""`, nil},
+		{"*int64", "0", nil},
+		{"Status", "0", nil},
+		{"[]string", "[]", nil},
+		{"map[string]int", "{}", nil},
+		{"time.Time", "null", nil},
+		{"*UserCtx", "", []string{"Email", "Status", "Manager", "Tags"}},
+	}
+	for _, test := range tests {
+		got := completionOf("v", test.typ, "", pugTestFields, pugTestEnums, nil)
+		if got.Value != test.wantValue {
+			t.Errorf("%s: got value %s, want %s", test.typ, got.Value, test.wantValue)
+		}
+		var names []string
+		for _, field := range got.Fields {
+			names = append(names, field.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(test.wantFields, ",") {
+			t.Errorf("%s: got fields %v, want %v", test.typ, names, test.wantFields)
+		}
+	}
+
+	user := completionOf("user", "UserCtx", "    ", pugTestFields, pugTestEnums, nil)
+	manager := user.Fields[2]
+	if manager.Value != "null" || len(manager.Fields) != 0 {
+		t.Errorf("recursive type: got %+v, want a null value", manager)
+	}
+	if manager.Indent != "        " {
+		t.Errorf("nested indent: got %q, want %q", manager.Indent, "        ")
+	}
+}
+
+func TestCheckPugVariables(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"known fields", "p= ctx.User.Email\np #{ctx.Lang}", false},
+		{"method call", "p= ctx.Created.Format('2006')", false},
+		{"unknown field", "p= ctx.User.Emial", true},
+	}
+	for _, test := range tests {
+		dir := t.TempDir()
+		pugDir := filepath.Join(dir, "src", "view", "pug")
+		if err := os.MkdirAll(pugDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		err := os.WriteFile(filepath.Join(pugDir, "page.pug"), []byte(test.content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		v := Vectra{ProjectPath: dir}
+		v.ViewTypes.Types = []VectraType[SimpleAttribute]{
+			{Name: "GlobalCtx", Attributes: pugTestFields["GlobalCtx"]},
+			{Name: "UserCtx", Attributes: pugTestFields["UserCtx"]},
+		}
+		v.Enums = []Enum{{Name: "Status", Values: []string{"Active"}}}
+
+		err = v.checkPugVariables()
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error: %t", test.name, err, test.wantErr)
+		}
+	}
+}
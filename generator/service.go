@@ -2,6 +2,7 @@ package generator
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -13,6 +14,12 @@ type Service struct {
 	Bodies        map[string]string              `yaml:"-"`
 }
 
+// HasSensitive tells if one of the exchange types of the service has a sensitive
+// attribute.
+func (s Service) HasSensitive() bool {
+	return slices.ContainsFunc(s.ExchangeTypes, VectraType[AttributeWithTag].HasSensitive)
+}
+
 type Method struct {
 	Name    string            `yaml:"name"`
 	Inputs  []SimpleAttribute `yaml:"inputs"`
@@ -43,7 +50,7 @@ func NewServices(cfg *Vectra) *Generator {
 		},
 		Report{
 			Files:   files,
-			Version: 1,
+			Version: 2,
 		}, cfg)
 
 	n := &Services{}
@@ -55,6 +62,11 @@ func NewServices(cfg *Vectra) *Generator {
 
 func (i *Services) Generate() {
 
+	if err := i.vectra.resolveMappings(); err != nil {
+		i.cancel("Invalid mappings", err)
+		return
+	}
+
 	for n, service := range i.vectra.Services {
 		i.vectra.Services[n].Bodies = extractFunctionBody(
 			i.vectra.ProjectPath + "/src/model/service/" +
@@ -15,6 +15,26 @@ type SpriteConfig struct {
 	OutputSpriteSvg string `yaml:"output_sprite_svg"`
 }
 
+// svgViewBox returns the viewBox of the svg element of an SVG file.
+func svgViewBox(path string) (string, error) {
+
+	doc := etree.NewDocument()
+	if err := doc.ReadFromFile(path); err != nil {
+		return "", err
+	}
+
+	svg := doc.SelectElement("svg")
+	if svg == nil {
+		return "", fmt.Errorf("svg element is not found in file %s", path)
+	}
+	viewBox := svg.SelectAttr("viewBox")
+	if viewBox == nil {
+		return "", fmt.Errorf("viewBox attribute is not found in svg %s", path)
+	}
+
+	return viewBox.Value, nil
+}
+
 func generateSpriteSvg(cfg *Vectra) {
 
 	var files []string
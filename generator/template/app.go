@@ -1,6 +1,7 @@
 package main
 
 import (
+	_ "Vectra/migrations"
 	"Vectra/src/controller"
 	"Vectra/src/model/i18n"
 	. "Vectra/src/model/service"
@@ -18,6 +19,7 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/session"
 	"log"
+	"os"
 	"strconv"
 	"time"
 )
@@ -28,10 +30,24 @@ type Host struct {
 
 func main() {
 
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1:])
+		return
+	}
+
+	CheckSchema(*GetStorage().DB)
+	CheckIndexes(*GetStorage().DB)
+	if err := GetApiV1().LoadFixtures(); err != nil {
+		log.Fatal(err)
+	}
+
 	err := i18n.GetInstance().SetUp(Langs...)
 	if err != nil {
 		panic(err)
 	}
+	if IsDev && WatchI18n {
+		go i18n.GetInstance().Watch(time.Second, Langs...)
+	}
 
 	hosts := map[string]*Host{}
 	store := session.New(session.Config{
@@ -89,6 +105,7 @@ func makeWebsite(store *session.Store, hosts map[string]*Host, currentDomain str
 		if sess.Get(SessionKeyForUserId) == nil {
 			sess.Set(SessionKeyForUserId, "")
 		}
+		controller.ResolveLang(ctx, sess)
 		sess.Save()
 		return ctx.Next()
 	}
@@ -104,11 +121,14 @@ func makeWebsite(store *session.Store, hosts map[string]*Host, currentDomain str
 	site.Use(logger.New(logger.Config{Format: "[${ip}]:${port} ${status} - ${method} ${path}\n"}))
 	site.Use(compress.New())
 	site.Use(favicon.New(favicon.Config{File: "./static/favicon.ico", URL: "/favicon.ico"}))
-	site.Use(firstLaunchHandler, fillDefaultSession, csrfHandler)
+	site.Use(firstLaunchHandler, fillDefaultSession)
 	hosts[currentDomain] = &Host{site}
 
-	controller.NewApiV1Controller(site.Group("/api/v1"), store)
-	controller.NewViewController(site.Group("/"), store)
+	// Middlewares used by name in the routes of the controllers.
+	controller.RegisterMiddleware("csrf", csrfHandler)
+
+	controller.NewApiV1Controller(site.Group(controller.ApiV1Prefix), store)
+	controller.NewViewController(site.Group(controller.ViewPrefix), store)
 }
 
 func createApp(hosts map[string]*Host) *fiber.App {
@@ -0,0 +1,127 @@
+package main
+
+import (
+	. "Vectra/src/model/service"
+	. "Vectra/src/model/storage"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+)
+
+// migrateStepCommand applies the next pending migration to the staging database given
+// as argument, in the child process run by migrate.
+const migrateStepCommand = "migrate-step"
+
+const commandUsage = `Usage: app [command]
+
+Without command, the server is started. Commands, run with the server stopped:
+  db migrate         Apply the pending migrations of the storage types.
+  db backup <file>   Write a full backup of the database.
+  db restore <file>  Load a backup in a new database.
+  db export <file>   Write every object and link of the storage types as JSON Lines.
+  db import <file>   Read an export into an empty database.
+  db fixtures        Load the fixtures of the fixtures folder (also done at startup).`
+
+// runCommand runs the administration command given in the arguments of the application
+// (e.g. `./app db migrate`) instead of starting the server.
+func runCommand(args []string) {
+
+	if len(args) < 2 || args[0] != "db" {
+		exitWithUsage()
+	}
+
+	var err error
+	switch args[1] {
+	case "migrate":
+		err = migrate()
+	case migrateStepCommand:
+		if len(args) != 3 {
+			exitWithUsage()
+		}
+		DbDirPath = args[2]
+		err = MigrateStep(*GetStorage().DB)
+	case "backup":
+		err = withFile(args, os.Create, func(f *os.File) error { return Backup(f) })
+	case "restore":
+		err = withFile(args, os.Open, func(f *os.File) error { return Restore(f) })
+	case "export":
+		err = withFile(args, os.Create, func(f *os.File) error {
+			return Export(*GetStorage().DB, f)
+		})
+	case "import":
+		err = withFile(args, os.Open, func(f *os.File) error {
+			return Import(*GetStorage().DB, f)
+		})
+	case "fixtures":
+		err = GetApiV1().LoadFixtures()
+	default:
+		exitWithUsage()
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// migrate applies the pending migrations one by one. A child process applies the next
+// migration to a staging copy of the database, closed when the process exits, then the
+// changes of the copy are committed to the database in one transaction (see
+// CommitStaging): a failing or interrupted migration leaves the database unchanged.
+func migrate() error {
+
+	stagingPath := DbDirPath + ".migration"
+	defer os.RemoveAll(stagingPath)
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	for {
+		if err := CopyDatabase(stagingPath); err != nil {
+			return fmt.Errorf("copy of the database before migration failed: %w", err)
+		}
+
+		cmd := exec.Command(executable, "db", migrateStepCommand, stagingPath)
+		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("migration failed, the database is unchanged: %w", err)
+		}
+
+		isChanged, err := CommitStaging(stagingPath)
+		if err != nil {
+			return fmt.Errorf("migration failed, the database is unchanged: %w", err)
+		}
+		if !isChanged {
+			log.Print("The database is at the schema v", SchemaVersion, ".")
+			return nil
+		}
+	}
+}
+
+// withFile opens the file given as third argument of the command and runs the action
+// with it.
+func withFile(args []string, open func(name string) (*os.File, error),
+	action func(f *os.File) error) error {
+
+	if len(args) != 3 {
+		exitWithUsage()
+	}
+
+	f, err := open(args[2])
+	if err != nil {
+		return err
+	}
+
+	if err := action(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func exitWithUsage() {
+	fmt.Println(commandUsage)
+	os.Exit(2)
+}
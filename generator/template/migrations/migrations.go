@@ -0,0 +1,7 @@
+// Package migrations holds the migrations of the storage types, applied by the
+// `db migrate` command of the application.
+//
+// A skeleton (e.g. 0002_update_user.go) is written by the types generator when storage
+// types change in a way existing records could not be decoded anymore: it registers
+// itself with storage.RegisterMigration and has to be completed before migrating.
+package migrations
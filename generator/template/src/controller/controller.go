@@ -6,18 +6,24 @@ import (
 	. "Vectra/src/model/storage"
 	"bytes"
 	"context"
+	"errors"
 	. "github.com/Phosmachina/FluentKV/reldb"
 	"github.com/go-playground/mold/v4/modifiers"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	. "github.com/gofiber/fiber/v2/middleware/session"
 	"io"
+	"log"
+	"reflect"
 	"strings"
 )
 
+const localsKeyForLang = "lang"
+
 var (
-	conform  = modifiers.New()
-	validate = validator.New()
+	conform     = modifiers.New()
+	validate    = newValidator()
+	middlewares = map[string]fiber.Handler{}
 )
 
 type Controller struct {
@@ -29,11 +35,93 @@ func NewController(router fiber.Router, store *Store) Controller {
 	return Controller{router: router, store: store}
 }
 
+// newValidator creates the validator used for exchange types and route inputs. Fields
+// are reported by their json name (or their path or query parameter name) so the client
+// can match them with the inputs of its forms. The enum tag checks that the value of an
+// enum is declared.
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(fieldName)
+	_ = v.RegisterValidation("enum", func(fl validator.FieldLevel) bool {
+		value, ok := fl.Field().Interface().(interface{ IsValid() bool })
+		return !ok || value.IsValid()
+	})
+	return v
+}
+
+// fieldName returns the name of the field known by the client: its json name, or its
+// path or query parameter name. It is empty for a field without these tags.
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "params", "query"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return ""
+}
+
+// RegisterMiddleware makes a handler available, under the given name, to the middleware
+// chains of the generated controllers. It must be called before creating controllers.
+func RegisterMiddleware(name string, handler fiber.Handler) {
+	middlewares[name] = handler
+}
+
+// Middleware returns the handler registered under the given name. The application
+// stops when the handler is missing because the route could not be protected.
+func Middleware(name string) fiber.Handler {
+	handler, ok := middlewares[name]
+	if !ok {
+		log.Fatalf("The middleware %s is not registered.", name)
+	}
+	return handler
+}
+
+// ResolveLang records the language of the request, read by Lang: the one chosen by the
+// visitor (kept in the session, see UpdateLang), else the preferred one of the
+// Accept-Language header among Langs, else DefaultLang.
+func ResolveLang(ctx *fiber.Ctx, sess *Session) {
+	lang, _ := sess.Get(SessionKeyForLang).(string)
+	if !IsLang(lang) {
+		lang = ctx.AcceptsLanguages(Langs...)
+	}
+	if lang == "" {
+		lang = DefaultLang
+	}
+	ctx.Locals(localsKeyForLang, lang)
+}
+
+// Lang returns the language of the request resolved by ResolveLang.
+func Lang(ctx *fiber.Ctx) string {
+	if lang, ok := ctx.Locals(localsKeyForLang).(string); ok {
+		return lang
+	}
+	return DefaultLang
+}
+
+// CheckAccess is the middleware rejecting, with a 403 Forbidden error, users whose role
+// is below the one required by the access rules for the matched route.
+func (c Controller) CheckAccess(ctx *fiber.Ctx) error {
+
+	sess, err := c.store.Get(ctx)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	userId := sess.Get(SessionKeyForUserId).(string)
+	if !GetApiV1().GetAccessManager().CheckAccessForRoute(userId, ctx.Route().Path) {
+		return fiber.ErrForbidden
+	}
+
+	return ctx.Next()
+}
+
 // HandleView is a function handling view logic for a certain page.
 // It first retrieves the user session from the store, using the given context.
-// If session retrieval is successful, it gets the API instance and extracts the user ID from the session.
-// It then checks if the user has access to the requested path and if not, it returns a 403 Forbidden error.
-// If a user has access, it creates a new bytes.Buffer and calls the provided writer function.
+// If session retrieval is successful, it extracts the user ID from the session: the
+// access to the page is already checked by the middleware chain of the route. The
+// writer reads the language of the request with Lang.
+// It creates a new bytes.Buffer and calls the provided writer function.
 // The writer function is supposed to write the required data into the provided buffer.
 // If writing is successful, it sets the context's content type to 'text/html; charset=UTF-8'
 // and sends the buffer's bytes as the response. If writing fails, it returns the error.
@@ -58,10 +146,6 @@ func HandleView(ctx *fiber.Ctx, controller ViewController,
 	}
 
 	userId := sess.Get(SessionKeyForUserId).(string)
-	if !GetApiV1().GetAccessManager().CheckAccessForRoute(userId,
-		ctx.Path()) {
-		return fiber.ErrForbidden
-	}
 
 	var buf = new(bytes.Buffer)
 	err = writer(buf, userId)
@@ -91,33 +175,65 @@ func HandleView(ctx *fiber.Ctx, controller ViewController,
 // This function executes if there are no errors from useInfo function.
 // The ObjWrapper that is passed to this function is the one that is returned from the useInfo function.
 //
+// Messages are localized in the language of the request (see Lang).
+//
 // This function will return an error if something goes wrong during the processing of the HTTP request, otherwise, it will return nil.
 func HandleRequest[T any, K IObject](ctx *fiber.Ctx, useInfo func(T) (error,
 	*ObjWrapper[K]), onSuccess func(*ObjWrapper[K])) error {
 
-	_i18n := i18n.GetInstance()
-
 	var data T
 	err := ctx.BodyParser(&data)
 	if err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(ReasonExch{
-			Reason: i18n.Error.InvalidRequestStructure(),
+			Reason: i18n.In(Lang(ctx)).Error.InvalidRequestStructure(),
 		})
 	}
 
-	conform.Struct(context.Background(), &data)
-	if validate.Struct(data) != nil {
+	return handleData(ctx, data, useInfo, onSuccess)
+}
+
+// HandleInput works as HandleRequest for routes declaring path or query parameters.
+// The input T, generated for the route, is bound from the path parameters, the query
+// string and the body (when the request has one) before being conformed and validated
+// with the same mod and validate tags as exchange types.
+func HandleInput[T any, K IObject](ctx *fiber.Ctx, useInfo func(T) (error,
+	*ObjWrapper[K]), onSuccess func(*ObjWrapper[K])) error {
+
+	var data T
+	err := ctx.ParamsParser(&data)
+	if err == nil {
+		err = ctx.QueryParser(&data)
+	}
+	if err == nil && len(ctx.Body()) > 0 {
+		err = ctx.BodyParser(&data)
+	}
+	if err != nil {
 		return ctx.Status(fiber.StatusBadRequest).JSON(ReasonExch{
-			Reason: i18n.Error.InvalidDataStructure(),
+			Reason: i18n.In(Lang(ctx)).Error.InvalidRequestStructure(),
 		})
 	}
 
+	return handleData(ctx, data, useInfo, onSuccess)
+}
+
+// handleData conforms and validates the data bound by HandleRequest or HandleInput,
+// then uses it and sends the resulting ReasonExch.
+func handleData[T any, K IObject](ctx *fiber.Ctx, data T, useInfo func(T) (error,
+	*ObjWrapper[K]), onSuccess func(*ObjWrapper[K])) error {
+
+	lang := Lang(ctx)
+
+	conform.Struct(context.Background(), &data)
+	if err := validate.Struct(data); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(validationReason(data, err, lang))
+	}
+
 	err, k := useInfo(data)
 	r := ReasonExch{}
 
 	if err != nil {
 		errName, _ := strings.CutPrefix(err.Error(), "Error")
-		r.Reason = _i18n.Get("error." + errName)
+		r.Reason = i18n.GetInstance().GetIn(lang, "error."+errName)
 		return ctx.Status(fiber.StatusBadRequest).JSON(r)
 	} else {
 		if onSuccess != nil {
@@ -127,13 +243,100 @@ func HandleRequest[T any, K IObject](ctx *fiber.Ctx, useInfo func(T) (error,
 	}
 }
 
+// ReloadI18n reads the translations of data/i18n again, to apply their changes without
+// restarting the application. The current translations are kept when a file is invalid,
+// and the error is sent as the reason.
+func ReloadI18n(ctx *fiber.Ctx) error {
+
+	if err := i18n.GetInstance().Reload(Langs...); err != nil {
+		log.Print("I18N | Reload FAILED: ", err)
+		return ctx.Status(fiber.StatusInternalServerError).JSON(ReasonExch{
+			Reason: err.Error(),
+		})
+	}
+
+	return ctx.JSON(ReasonExch{})
+}
+
+// validationReason builds the ReasonExch sent when an exchange type does not pass the
+// validation. Reason keeps the generic message while Fields lists each failing field
+// with the violated tag and a message localized from the validation section of i18n
+// (the default key is used for tags without translation).
+func validationReason(data any, err error, lang string) ReasonExch {
+
+	_i18n := i18n.GetInstance()
+	r := ReasonExch{Reason: i18n.In(lang).Error.InvalidDataStructure()}
+
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return r
+	}
+
+	for _, e := range errs {
+		key := "validation." + e.Tag()
+		if !_i18n.ExistsIn(lang, key) {
+			key = "validation.default"
+		}
+
+		var message string
+		if e.Param() != "" {
+			message = _i18n.GetIn(lang, key, validationParam(data, e))
+		} else {
+			message = _i18n.GetIn(lang, key)
+		}
+
+		r.Fields = append(r.Fields, FieldErrorExch{
+			Field:   e.Field(),
+			Tag:     e.Tag(),
+			Message: message,
+		})
+	}
+
+	return r
+}
+
+// validationParam returns the parameter of a failing tag. The tags comparing fields
+// (e.g. eqfield) take the Go name of the other field: it is replaced by the name known by
+// the client, found from the struct of the failing field.
+func validationParam(data any, e validator.FieldError) string {
+
+	if !strings.HasSuffix(e.Tag(), "field") {
+		return e.Param()
+	}
+
+	// The namespace starts with the name of the type and ends with the failing field.
+	t := reflect.TypeOf(data)
+	path := strings.Split(e.StructNamespace(), ".")
+	path = append(path[1:len(path)-1], e.Param())
+	for i, name := range path {
+		name, _, _ = strings.Cut(name, "[")
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice ||
+			t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return e.Param()
+		}
+		field, ok := t.FieldByName(name)
+		if !ok {
+			return e.Param()
+		}
+		if i == len(path)-1 && fieldName(field) != "" {
+			return fieldName(field)
+		}
+		t = field.Type
+	}
+
+	return e.Param()
+}
+
 func checkAccess[T IObject](ctx *fiber.Ctx, sess *Session, idOfT string) error {
 
 	userId := sess.Get(SessionKeyForUserId).(string)
 
 	if !CheckAccessForTable[T](userId, idOfT) {
 		return ctx.Status(fiber.StatusForbidden).JSON(ReasonExch{
-			Reason: i18n.Error.InsufficientRoleLevel(),
+			Reason: i18n.In(Lang(ctx)).Error.InsufficientRoleLevel(),
 		})
 	}
 
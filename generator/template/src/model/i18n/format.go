@@ -0,0 +1,122 @@
+package i18n
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/go-ini/ini"
+	"gopkg.in/yaml.v3"
+	"strconv"
+	"strings"
+)
+
+// decoders read the translation files of data/i18n by extension. The entries of a file
+// are prefixed by its path in the folder of the language; nested YAML and JSON objects
+// are joined with dots (view/index.yml with title under page gives view.index.page.title).
+var decoders = map[string]func(data []byte) (map[string]string, error){
+	".ini":  decodeIni,
+	".yml":  decodeYaml,
+	".yaml": decodeYaml,
+	".json": decodeJson,
+	".po":   decodePo,
+}
+
+func decodeIni(data []byte) (map[string]string, error) {
+	cfg, err := ini.LoadSources(ini.LoadOptions{IgnoreInlineComment: true}, data)
+	if err != nil {
+		return nil, err
+	}
+	dic := map[string]string{}
+	for _, k := range cfg.Section("").Keys() {
+		dic[k.Name()] = k.Value()
+	}
+	return dic, nil
+}
+
+func decodeYaml(data []byte) (map[string]string, error) {
+	var values map[string]any
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	dic := map[string]string{}
+	return dic, flatten("", values, dic)
+}
+
+func decodeJson(data []byte) (map[string]string, error) {
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	dic := map[string]string{}
+	return dic, flatten("", values, dic)
+}
+
+func flatten(prefix string, values map[string]any, dic map[string]string) error {
+	for key, value := range values {
+		switch v := value.(type) {
+		case map[string]any:
+			if err := flatten(prefix+key+".", v, dic); err != nil {
+				return err
+			}
+		case []any:
+			return fmt.Errorf("%s%s: a translation could not be a list", prefix, key)
+		case nil:
+			dic[prefix+key] = ""
+		default:
+			dic[prefix+key] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
+
+// decodePo reads the entries of a gettext PO file whose msgid are the keys. The header
+// and the untranslated entries are skipped; msgstr[0] is the translation of an entry
+// with plural forms.
+func decodePo(data []byte) (map[string]string, error) {
+
+	dic := map[string]string{}
+	var msgid, msgstr *strings.Builder
+	var current *strings.Builder
+
+	flush := func() {
+		if msgid != nil && msgstr != nil && msgid.Len() > 0 && msgstr.Len() > 0 {
+			dic[msgid.String()] = msgstr.String()
+		}
+		msgid, msgstr, current = nil, nil, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		keyword, rest, _ := strings.Cut(line, " ")
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, `"`):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: string without keyword", n)
+			}
+		case keyword == "msgid":
+			flush()
+			msgid = &strings.Builder{}
+			current, line = msgid, rest
+		case keyword == "msgstr" || keyword == "msgstr[0]":
+			msgstr = &strings.Builder{}
+			current, line = msgstr, rest
+		default:
+			// msgctxt, msgid_plural and the other plural forms are not used.
+			current, line = &strings.Builder{}, rest
+		}
+
+		value, err := strconv.Unquote(strings.TrimSpace(line))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		current.WriteString(value)
+	}
+	flush()
+
+	return dic, scanner.Err()
+}
@@ -1,10 +1,8 @@
 package i18n
 
 import (
-	. "Vectra/src/model/service"
 	"Vectra/src/model/storage"
 	"fmt"
-	"github.com/go-ini/ini"
 	"os"
 	"path/filepath"
 	"strings"
@@ -27,22 +25,37 @@ func GetInstance() *I18n {
 	return instance
 }
 
+// SetUp loads the translations of the languages from their folder of data/i18n.
 func (i *I18n) SetUp(langs ...string) error {
-	i.mu.Lock()
-	defer i.mu.Unlock()
+	return i.Reload(langs...)
+}
 
+// Reload reads the translations of the languages again and replaces the current ones,
+// which are kept when a file is invalid. The translations are read before taking the
+// lock, so that requests are only blocked by the swap.
+func (i *I18n) Reload(langs ...string) error {
+
+	dic := make(map[string]map[string]string)
 	for _, lang := range langs {
 		path := filepath.Join(storage.I18nDirPath, lang)
-		err := i.loadData(path, lang, "")
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("the folder of the language %s is missing: %w", lang, err)
+		}
+		dic[lang] = make(map[string]string)
+		err := loadData(path, "", dic[lang])
 		if err != nil {
 			return err
 		}
 	}
 
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.dic = dic
+
 	return nil
 }
 
-func (i *I18n) loadData(path string, lang string, prefix string) error {
+func loadData(path string, prefix string, dic map[string]string) error {
 
 	entries, err := os.ReadDir(path)
 	if err != nil {
@@ -52,24 +65,24 @@ func (i *I18n) loadData(path string, lang string, prefix string) error {
 	for _, entry := range entries {
 		key := entry.Name()
 		if entry.IsDir() {
-			err := i.loadData(filepath.Join(path, key), lang, prefix+key+".")
+			err := loadData(filepath.Join(path, key), prefix+key+".", dic)
 			if err != nil {
 				return err
 			}
-		} else if strings.HasSuffix(key, ".ini") {
-			if _, ok := i.dic[lang]; !ok {
-				i.dic[lang] = make(map[string]string)
-			}
-			fullKey := prefix + strings.TrimSuffix(key, ".ini")
+		} else if decode, ok := decoders[filepath.Ext(key)]; ok {
+			fullKey := prefix + strings.TrimSuffix(key, filepath.Ext(key))
 
 			data, err := os.ReadFile(filepath.Join(path, key))
 			if err != nil {
 				return err
 			}
 
-			cfg, _ := ini.LoadSources(ini.LoadOptions{}, data)
-			for _, k := range cfg.Section("").Keys() {
-				i.dic[lang][fullKey+"."+k.Name()] = k.Value()
+			entries, err := decode(data)
+			if err != nil {
+				return fmt.Errorf("%s: %w", filepath.Join(path, key), err)
+			}
+			for k, value := range entries {
+				dic[fullKey+"."+k] = value
 			}
 		}
 	}
@@ -77,21 +90,56 @@ func (i *I18n) loadData(path string, lang string, prefix string) error {
 	return nil
 }
 
-// Get is a method of the I18n type that allows for dynamic string localization.
-// It retrieves the translation string associated with the provided key.
+// Exists reports whether a translation string is associated with the key for the
+// default language.
+func (i *I18n) Exists(key string) bool {
+	return i.ExistsIn(storage.DefaultLang, key)
+}
+
+// ExistsIn reports whether a translation string is associated with the key for the
+// language or, as a fallback, for the default language.
+func (i *I18n) ExistsIn(lang string, key string) bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	_, ok := i.lookup(lang, key)
+	return ok
+}
+
+// lookup returns the translation string of the key for the language, falling back to
+// the default language when the language or the key is missing.
+func (i *I18n) lookup(lang string, key string) (string, bool) {
+	if val, ok := i.dic[lang][key]; ok {
+		return val, true
+	}
+	val, ok := i.dic[storage.DefaultLang][key]
+	return val, ok
+}
+
+// Get returns the translation of the key in the default language (see GetIn).
+func (i *I18n) Get(key string, args ...interface{}) string {
+	return i.GetIn(storage.DefaultLang, key, args...)
+}
+
+// GetIn is a method of the I18n type that allows for dynamic string localization.
+// It retrieves the translation string associated with the provided key in the language,
+// or in the default language when there is none.
 // The method also supports pluralization of the translation based on the first argument in args,
-// when it's an integer and different from 1 (which stands for singular form).
+// when it's an integer: the key of its CLDR plural category is used (see PluralCategory).
 //
 // Parameters:
 //
+// - lang: The language of the translation, e.g. the one of the request.
+//
 // - key: The key associated with the translation string in the dictionary.
 //
-// - args: The optional arguments which can be used for string formatting and pluralization.
+// - args: The optional arguments which can be used for string formatting and pluralization
+// (given to the named placeholders in their order of appearance, see FormatIn).
 //
 // Returns:
 //
 // - The formatted translation string associated with the key if it exists, otherwise "Key not found".
-func (i *I18n) Get(key string, args ...interface{}) string {
+func (i *I18n) GetIn(lang string, key string, args ...interface{}) string {
 	i.mu.RLock()
 	defer i.mu.RUnlock()
 
@@ -99,18 +147,44 @@ func (i *I18n) Get(key string, args ...interface{}) string {
 		// If there are parameters and the first one is an integer,
 		// check its value for pluralization.
 		if count, ok := args[0].(int); ok {
-			if count != 1 {
-				key = fmt.Sprintf("%s_plural", key)
-			} else {
-				key = fmt.Sprintf("%s_singular", key)
+			if val, ok := i.plural(lang, key, count); ok {
+				return formatPositional(lang, val, args)
 			}
 		}
 	}
 
-	if val, ok := i.dic[GetApiV1().GetStore().Config.CurrentLang][key]; ok {
-		sprintf := fmt.Sprintf(val, args...)
-		return sprintf
+	if val, ok := i.lookup(lang, key); ok {
+		return formatPositional(lang, val, args)
 	} else {
 		return "Key not found"
 	}
 }
+
+// FormatIn returns the translation of the key in the language (or in the default
+// language when there is none) with its named placeholders replaced by the arguments
+// (see message), otherwise "Key not found".
+func (i *I18n) FormatIn(lang string, key string, args map[string]any) string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if val, ok := i.lookup(lang, key); ok {
+		return formatMessage(lang, val, args)
+	}
+	return "Key not found"
+}
+
+// formatPositional formats a translation with fmt verbs, or with named placeholders
+// given in their order of appearance.
+func formatPositional(lang string, val string, args []interface{}) string {
+	if !isMessage(val) {
+		return fmt.Sprintf(val, args...)
+	}
+
+	named := map[string]any{}
+	for n, name := range placeholderNames(val) {
+		if n < len(args) {
+			named[name] = args[n]
+		}
+	}
+	return formatMessage(lang, val, named)
+}
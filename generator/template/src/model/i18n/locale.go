@@ -0,0 +1,226 @@
+package i18n
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// localePrefix is the prefix of the keys of locale.ini, the formatting patterns of a
+// language. They do not give translation functions but the Formatter of the language.
+const localePrefix = "locale."
+
+// defaultPatterns are used for the patterns missing in the language and in the default
+// language.
+var defaultPatterns = map[string]string{
+	"date":         "01/02/2006",
+	"time":         "3:04 PM",
+	"datetime":     "01/02/2006 3:04 PM",
+	"long_date":    "Monday, January 2, 2006",
+	"months":       "January,February,March,April,May,June,July,August,September,October,November,December",
+	"months_short": "Jan,Feb,Mar,Apr,May,Jun,Jul,Aug,Sep,Oct,Nov,Dec",
+	"days":         "Sunday,Monday,Tuesday,Wednesday,Thursday,Friday,Saturday",
+	"days_short":   "Sun,Mon,Tue,Wed,Thu,Fri,Sat",
+	"decimal":      ".",
+	"group":        ",",
+	"currency":     "{symbol}{amount}",
+	"percent":      "{value}%",
+	"just_now":     "just now",
+	"seconds_ago":  "{count, plural, one {# second ago} other {# seconds ago}}",
+	"minutes_ago":  "{count, plural, one {# minute ago} other {# minutes ago}}",
+	"hours_ago":    "{count, plural, one {# hour ago} other {# hours ago}}",
+	"days_ago":     "{count, plural, one {# day ago} other {# days ago}}",
+	"months_ago":   "{count, plural, one {# month ago} other {# months ago}}",
+	"years_ago":    "{count, plural, one {# year ago} other {# years ago}}",
+	"in_seconds":   "{count, plural, one {in # second} other {in # seconds}}",
+	"in_minutes":   "{count, plural, one {in # minute} other {in # minutes}}",
+	"in_hours":     "{count, plural, one {in # hour} other {in # hours}}",
+	"in_days":      "{count, plural, one {in # day} other {in # days}}",
+	"in_months":    "{count, plural, one {in # month} other {in # months}}",
+	"in_years":     "{count, plural, one {in # year} other {in # years}}",
+}
+
+// currencySymbols are the symbols of the common currencies; a language may give its
+// own with a currency_<code> key (e.g. currency_USD = $US).
+var currencySymbols = map[string]string{
+	"USD": "$", "EUR": "€", "GBP": "£", "JPY": "¥", "CNY": "¥", "INR": "₹", "KRW": "₩",
+	"RUB": "₽", "BRL": "R$", "CHF": "CHF", "CAD": "CA$", "AUD": "A$", "PLN": "zł",
+}
+
+// currencyDecimals are the currencies without minor unit; the others have 2 decimals.
+var currencyDecimals = map[string]int{"JPY": 0, "KRW": 0}
+
+// Formatter formats dates, relative times, numbers, currencies and percentages in a
+// language, with the patterns of its locale.ini: Go layouts for dates (whose month and
+// day names are replaced by the ones of the language), separators for numbers and
+// ICU-style messages for the others.
+type Formatter struct {
+	lang string
+}
+
+// FormatterIn returns the Formatter of the language.
+func FormatterIn(lang string) Formatter {
+	return Formatter{lang: lang}
+}
+
+// pattern returns the pattern of the language, else the one of the default language or
+// the built-in one.
+func (f Formatter) pattern(name string) string {
+	i := GetInstance()
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if val, ok := i.lookup(f.lang, localePrefix+name); ok {
+		return val
+	}
+	return defaultPatterns[name]
+}
+
+// Date formats the date of t with the date pattern, e.g. 02/01/2006.
+func (f Formatter) Date(t time.Time) string {
+	return f.layout(t, f.pattern("date"))
+}
+
+// Time formats the time of t with the time pattern, e.g. 15:04.
+func (f Formatter) Time(t time.Time) string {
+	return f.layout(t, f.pattern("time"))
+}
+
+// DateTime formats t with the datetime pattern, e.g. 02/01/2006 15:04.
+func (f Formatter) DateTime(t time.Time) string {
+	return f.layout(t, f.pattern("datetime"))
+}
+
+// LongDate formats the date of t with the long_date pattern, e.g. Monday 2 January 2006.
+func (f Formatter) LongDate(t time.Time) string {
+	return f.layout(t, f.pattern("long_date"))
+}
+
+// Relative describes t relatively to now, e.g. 3 hours ago or in 2 days.
+func (f Formatter) Relative(t time.Time) string {
+
+	d := time.Until(t)
+	prefix, suffix := "in_", ""
+	if d < 0 {
+		d = -d
+		prefix, suffix = "", "_ago"
+	}
+
+	var unit string
+	var count int
+	switch {
+	case d < 10*time.Second:
+		return f.pattern("just_now")
+	case d < time.Minute:
+		unit, count = "seconds", int(d/time.Second)
+	case d < time.Hour:
+		unit, count = "minutes", int(d/time.Minute)
+	case d < 24*time.Hour:
+		unit, count = "hours", int(d/time.Hour)
+	case d < 30*24*time.Hour:
+		unit, count = "days", int(d/(24*time.Hour))
+	case d < 365*24*time.Hour:
+		unit, count = "months", int(d/(30*24*time.Hour))
+	default:
+		unit, count = "years", int(d/(365*24*time.Hour))
+	}
+
+	return formatMessage(f.lang, f.pattern(prefix+unit+suffix), map[string]any{"count": count})
+}
+
+// Number formats v with the decimals, the decimal separator and the group separator of
+// the language, e.g. 1,234.57 or 1 234,57.
+func (f Formatter) Number(v float64, decimals int) string {
+
+	digits := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(digits, ".")
+
+	group := f.pattern("group")
+	var b strings.Builder
+	if v < 0 && strings.Trim(digits, "0.") != "" {
+		b.WriteString("-")
+	}
+	for n, c := range integer {
+		if n > 0 && (len(integer)-n)%3 == 0 {
+			b.WriteString(group)
+		}
+		b.WriteRune(c)
+	}
+	if fraction != "" {
+		b.WriteString(f.pattern("decimal"))
+		b.WriteString(fraction)
+	}
+
+	return b.String()
+}
+
+// Currency formats the amount in the currency of the ISO 4217 code with the currency
+// pattern, e.g. $1,234.50 or 1 234,50 €.
+func (f Formatter) Currency(amount float64, code string) string {
+
+	code = strings.ToUpper(code)
+	decimals, ok := currencyDecimals[code]
+	if !ok {
+		decimals = 2
+	}
+	symbol := f.pattern("currency_" + code)
+	if symbol == "" {
+		if symbol, ok = currencySymbols[code]; !ok {
+			symbol = code
+		}
+	}
+
+	return formatMessage(f.lang, f.pattern("currency"), map[string]any{
+		"amount": f.Number(amount, decimals),
+		"symbol": symbol,
+	})
+}
+
+// Percent formats the ratio as a percentage with the percent pattern, e.g. 0.256 with
+// 1 decimal gives 25.6% or 25,6 %.
+func (f Formatter) Percent(ratio float64, decimals int) string {
+	return formatMessage(f.lang, f.pattern("percent"), map[string]any{
+		"value": f.Number(ratio*100, decimals),
+	})
+}
+
+// layout formats t with a Go layout whose month and day names are the ones of the
+// language (the English ones when missing): the layout is split around the names,
+// formatted separately.
+func (f Formatter) layout(t time.Time, layout string) string {
+
+	names := []struct {
+		token string
+		names string
+		index int
+	}{
+		{"January", "months", int(t.Month()) - 1},
+		{"Jan", "months_short", int(t.Month()) - 1},
+		{"Monday", "days", int(t.Weekday())},
+		{"Mon", "days_short", int(t.Weekday())},
+	}
+
+	var b strings.Builder
+	for layout != "" {
+		first, end, name := len(layout), 0, ""
+		for _, n := range names {
+			if i := strings.Index(layout, n.token); i != -1 && i < first {
+				first, end, name = i, i+len(n.token), t.Format(n.token)
+				if values := strings.Split(f.pattern(n.names), ","); n.index < len(values) {
+					name = strings.TrimSpace(values[n.index])
+				}
+			}
+		}
+		if first > 0 {
+			b.WriteString(t.Format(layout[:first]))
+		}
+		if first == len(layout) {
+			break
+		}
+		b.WriteString(name)
+		layout = layout[end:]
+	}
+
+	return b.String()
+}
@@ -0,0 +1,166 @@
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// message formats an ICU-style translation: {name} is replaced by the argument of the
+// name, {name, plural, one {# day} other {# days}} picks the option of the CLDR plural
+// category of the argument (or an exact =N option; # is the count) and
+// {name, select, admin {...} other {...}} the option of the argument. An apostrophe
+// quotes the braces and # which follow it, two apostrophes are an apostrophe.
+type message struct {
+	src   string
+	pos   int
+	lang  string
+	args  map[string]any
+	names []string
+}
+
+// formatMessage formats the translation with the named arguments.
+func formatMessage(lang string, src string, args map[string]any) string {
+	m := message{src: src, lang: lang, args: args}
+	return m.text(nil)
+}
+
+// placeholderNames returns the names of the placeholders of the translation in their
+// order of appearance, to give them positional arguments.
+func placeholderNames(src string) []string {
+	m := message{src: src, args: map[string]any{}}
+	m.text(nil)
+	return m.names
+}
+
+// isMessage reports whether the translation has named placeholders instead of fmt verbs.
+func isMessage(src string) bool {
+	return len(placeholderNames(src)) > 0
+}
+
+// text reads a (sub-)message up to its closing brace or the end. count is the value of
+// # in the option of a plural.
+func (m *message) text(count *int) string {
+	var b strings.Builder
+	for m.pos < len(m.src) {
+		c := m.src[m.pos]
+		switch {
+		case c == '}':
+			return b.String()
+		case c == '{':
+			m.pos++
+			b.WriteString(m.argument())
+		case c == '#' && count != nil:
+			m.pos++
+			b.WriteString(strconv.Itoa(*count))
+		case c == '\'':
+			m.quoted(&b)
+		default:
+			b.WriteByte(c)
+			m.pos++
+		}
+	}
+	return b.String()
+}
+
+func (m *message) quoted(b *strings.Builder) {
+	m.pos++
+	if m.pos >= len(m.src) {
+		b.WriteByte('\'')
+		return
+	}
+	if m.src[m.pos] == '\'' {
+		b.WriteByte('\'')
+		m.pos++
+		return
+	}
+	if !strings.ContainsRune("{}#", rune(m.src[m.pos])) {
+		b.WriteByte('\'')
+		return
+	}
+	end := strings.IndexByte(m.src[m.pos:], '\'')
+	if end == -1 {
+		end = len(m.src) - m.pos
+	}
+	b.WriteString(m.src[m.pos : m.pos+end])
+	m.pos = min(m.pos+end+1, len(m.src))
+}
+
+// argument formats an argument after its opening brace.
+func (m *message) argument() string {
+
+	name := strings.TrimSpace(m.until(",}"))
+	m.addName(name)
+	value, ok := m.args[name]
+
+	if m.pos >= len(m.src) || m.src[m.pos] == '}' {
+		m.pos++
+		if !ok {
+			return "{" + name + "}"
+		}
+		return fmt.Sprint(value)
+	}
+
+	m.pos++
+	kind := strings.TrimSpace(m.until(",}"))
+	if m.pos < len(m.src) && m.src[m.pos] == ',' {
+		m.pos++
+	}
+
+	var count *int
+	selected := fmt.Sprint(value)
+	if kind == "plural" {
+		n, _ := value.(int)
+		count = &n
+		selected = PluralCategory(m.lang, n)
+	}
+
+	options := map[string]string{}
+	for m.pos < len(m.src) {
+		m.skipSpaces()
+		if m.pos >= len(m.src) || m.src[m.pos] == '}' {
+			break
+		}
+		selector := strings.TrimSpace(m.until("{}"))
+		if m.pos >= len(m.src) || m.src[m.pos] != '{' {
+			break
+		}
+		m.pos++
+		options[selector] = m.text(count)
+		m.pos++
+	}
+	m.pos++
+
+	if count != nil {
+		if option, ok := options["="+strconv.Itoa(*count)]; ok {
+			return option
+		}
+	}
+	if option, ok := options[selected]; ok {
+		return option
+	}
+	return options["other"]
+}
+
+func (m *message) addName(name string) {
+	for _, n := range m.names {
+		if n == name {
+			return
+		}
+	}
+	m.names = append(m.names, name)
+}
+
+func (m *message) until(chars string) string {
+	start := m.pos
+	for m.pos < len(m.src) && !strings.ContainsRune(chars, rune(m.src[m.pos])) {
+		m.pos++
+	}
+	return m.src[start:m.pos]
+}
+
+func (m *message) skipSpaces() {
+	for m.pos < len(m.src) && strings.ContainsRune(" \t\n", rune(m.src[m.pos])) {
+		m.pos++
+	}
+}
@@ -0,0 +1,206 @@
+package i18n
+
+import (
+	"Vectra/src/model/storage"
+	"strings"
+)
+
+// pluralSuffixes gives the suffixes of the keys of each CLDR plural category: a
+// pluralized translation has a key by category of its language (e.g. day_one and
+// day_other). The former _singular and _plural suffixes stand for one and other.
+var pluralSuffixes = map[string][]string{
+	"zero":  {"_zero"},
+	"one":   {"_one", "_singular"},
+	"two":   {"_two"},
+	"few":   {"_few"},
+	"many":  {"_many"},
+	"other": {"_other", "_plural"},
+}
+
+// pluralRules gives the CLDR plural category of an integer count by language. The
+// languages without rule use the one of English (one for 1, other otherwise). As counts
+// are integers, the many category used by French or Spanish for millions is ignored.
+var pluralRules = map[string]func(n int) string{
+	"ar": func(n int) string {
+		switch {
+		case n == 0:
+			return "zero"
+		case n == 1:
+			return "one"
+		case n == 2:
+			return "two"
+		case n%100 >= 3 && n%100 <= 10:
+			return "few"
+		case n%100 >= 11:
+			return "many"
+		}
+		return "other"
+	},
+	"cs": czechRule,
+	"cy": func(n int) string {
+		switch n {
+		case 0:
+			return "zero"
+		case 1:
+			return "one"
+		case 2:
+			return "two"
+		case 3:
+			return "few"
+		case 6:
+			return "many"
+		}
+		return "other"
+	},
+	"fr": frenchRule,
+	"ga": func(n int) string {
+		switch {
+		case n == 1:
+			return "one"
+		case n == 2:
+			return "two"
+		case n >= 3 && n <= 6:
+			return "few"
+		case n >= 7 && n <= 10:
+			return "many"
+		}
+		return "other"
+	},
+	"he": func(n int) string {
+		switch n {
+		case 1:
+			return "one"
+		case 2:
+			return "two"
+		}
+		return "other"
+	},
+	"hr": serbianRule,
+	"ja": otherRule,
+	"ko": otherRule,
+	"lt": func(n int) string {
+		switch {
+		case n%10 == 1 && !(n%100 >= 11 && n%100 <= 19):
+			return "one"
+		case n%10 >= 2 && !(n%100 >= 11 && n%100 <= 19):
+			return "few"
+		}
+		return "other"
+	},
+	"lv": func(n int) string {
+		switch {
+		case n%10 == 0 || (n%100 >= 11 && n%100 <= 19):
+			return "zero"
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		}
+		return "other"
+	},
+	"pl": func(n int) string {
+		switch {
+		case n == 1:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && !(n%100 >= 12 && n%100 <= 14):
+			return "few"
+		}
+		return "many"
+	},
+	"pt": frenchRule,
+	"ro": func(n int) string {
+		switch {
+		case n == 1:
+			return "one"
+		case n == 0 || (n%100 >= 1 && n%100 <= 19):
+			return "few"
+		}
+		return "other"
+	},
+	"ru": russianRule,
+	"sk": czechRule,
+	"sl": func(n int) string {
+		switch n % 100 {
+		case 1:
+			return "one"
+		case 2:
+			return "two"
+		case 3, 4:
+			return "few"
+		}
+		return "other"
+	},
+	"sr": serbianRule,
+	"th": otherRule,
+	"uk": russianRule,
+	"vi": otherRule,
+	"zh": otherRule,
+}
+
+func otherRule(int) string { return "other" }
+
+func frenchRule(n int) string {
+	if n == 0 || n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+func czechRule(n int) string {
+	switch {
+	case n == 1:
+		return "one"
+	case n >= 2 && n <= 4:
+		return "few"
+	}
+	return "other"
+}
+
+func russianRule(n int) string {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return "one"
+	case n%10 >= 2 && n%10 <= 4 && !(n%100 >= 12 && n%100 <= 14):
+		return "few"
+	}
+	return "many"
+}
+
+func serbianRule(n int) string {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return "one"
+	case n%10 >= 2 && n%10 <= 4 && !(n%100 >= 12 && n%100 <= 14):
+		return "few"
+	}
+	return "other"
+}
+
+// PluralCategory returns the CLDR plural category (zero, one, two, few, many or other)
+// of the count in the language, e.g. one for 0 in French but other in English.
+func PluralCategory(lang string, count int) string {
+	if count < 0 {
+		count = -count
+	}
+	base, _, _ := strings.Cut(strings.ToLower(strings.ReplaceAll(lang, "_", "-")), "-")
+	if rule, ok := pluralRules[base]; ok {
+		return rule(count)
+	}
+	if count == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+// plural returns the form of the key for the count: the one of its plural category in
+// the language, else its other form, then the same in the default language.
+func (i *I18n) plural(lang string, key string, count int) (string, bool) {
+	for _, l := range []string{lang, storage.DefaultLang} {
+		for _, category := range []string{PluralCategory(l, count), "other"} {
+			for _, suffix := range pluralSuffixes[category] {
+				if val, ok := i.dic[l][key+suffix]; ok {
+					return val, true
+				}
+			}
+		}
+	}
+	return "", false
+}
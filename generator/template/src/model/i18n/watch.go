@@ -0,0 +1,51 @@
+package i18n
+
+import (
+	"Vectra/src/model/storage"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"log"
+	"path/filepath"
+	"time"
+)
+
+// Watch reloads the translations of the languages when a file of data/i18n is added,
+// edited or removed, checking the files at each interval. It is meant for development
+// (see reload_app of the i18n watcher in project.yml): an invalid file is logged and
+// the previous translations are kept until it is fixed.
+func (i *I18n) Watch(interval time.Duration, langs ...string) {
+
+	last := filesState()
+	for range time.Tick(interval) {
+		state := filesState()
+		if state == last {
+			continue
+		}
+		last = state
+
+		if err := i.Reload(langs...); err != nil {
+			log.Print("I18N | Reload FAILED: ", err)
+		} else {
+			log.Print("I18N | Reload DONE.")
+		}
+	}
+}
+
+// filesState returns a hash of the path, the size and the modification time of the
+// files of data/i18n.
+func filesState() uint64 {
+	h := fnv.New64a()
+	_ = filepath.WalkDir(storage.I18nDirPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		_, _ = fmt.Fprintf(h, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	return h.Sum64()
+}
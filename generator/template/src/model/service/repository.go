@@ -0,0 +1,127 @@
+package service
+
+import (
+	. "Vectra/src/model/storage"
+	"errors"
+	. "github.com/Phosmachina/FluentKV/reldb"
+)
+
+var ErrorInsufficientRoleLevel = errors.New("ErrorInsufficientRoleLevel")
+
+// Repository gives access to the objects of a table for a user: each operation is
+// checked with the access rules of the table (see CheckAccessForTable). A system
+// repository skips these checks and is reserved to the internal logic of services.
+//
+// Typed repositories, with finders for indexed attributes, are generated for each
+// storage type (e.g. NewUserRepository).
+type Repository[T IObject] struct {
+	db       IRelationalDB
+	userId   string
+	isSystem bool
+	helpers  TableHelpers[T]
+}
+
+// TableHelpers are the generated functions writing the objects of a table: they keep the
+// indexes and the relations of the table consistent (e.g. InsertUser, UpdateUser and
+// DeleteUser).
+type TableHelpers[T IObject] struct {
+	Insert func(db IRelationalDB, obj T) (*ObjWrapper[T], error)
+	Update func(db IRelationalDB, id string, editor func(value *T)) (*ObjWrapper[T], error)
+	Delete func(db IRelationalDB, id string)
+}
+
+func newRepository[T IObject](userId string, isSystem bool, helpers TableHelpers[T]) Repository[T] {
+	return Repository[T]{
+		db:       *GetStorage().DB,
+		userId:   userId,
+		isSystem: isSystem,
+		helpers:  helpers,
+	}
+}
+
+func (r Repository[T]) canAccess(id string) bool {
+	return r.isSystem || CheckAccessForTable[T](r.userId, id)
+}
+
+// Create inserts the object if the role of the user reaches the level of the table.
+// ErrorNotUnique is returned when a unique attribute of the object is already used.
+func (r Repository[T]) Create(obj T) (*ObjWrapper[T], error) {
+	if !r.isSystem && !CheckLevelForTable[T](r.userId) {
+		return nil, ErrorInsufficientRoleLevel
+	}
+	return r.helpers.Insert(r.db, obj)
+}
+
+// Get returns the object with the given id. ErrorNotFound is returned for a missing id
+// before the access of the user is checked.
+func (r Repository[T]) Get(id string) (*ObjWrapper[T], error) {
+	wrp := Get[T](r.db, id)
+	if wrp == nil {
+		return nil, ErrorNotFound
+	}
+	if !r.canAccess(id) {
+		return nil, ErrorInsufficientRoleLevel
+	}
+	return wrp, nil
+}
+
+// Update edits the object with the given id and returns its new value.
+// ErrorNotUnique is returned when the edition takes a unique value already used.
+func (r Repository[T]) Update(id string, editor func(value *T)) (*ObjWrapper[T], error) {
+	if _, err := r.Get(id); err != nil {
+		return nil, err
+	}
+	return r.helpers.Update(r.db, id, editor)
+}
+
+// Delete removes the object with the given id, and the objects linked to it by a
+// relation with cascade delete.
+func (r Repository[T]) Delete(id string) error {
+	if _, err := r.Get(id); err != nil {
+		return err
+	}
+	r.helpers.Delete(r.db, id)
+	return nil
+}
+
+// List returns the page (starting at 1) of the objects accessible by the user, with at
+// most size objects by page. The ids of the table are iterated until the page is filled,
+// so only its objects are loaded; a page out of range is empty.
+func (r Repository[T]) List(page int, size int) ([]*ObjWrapper[T], error) {
+	wrps := []*ObjWrapper[T]{}
+	if page < 1 || size < 1 {
+		return wrps, nil
+	}
+
+	skip := (page - 1) * size
+	var ids []string
+	r.db.RawIterKey(MakePrefix(NameOfStruct[T]()), func(id string) bool {
+		if !r.canAccess(id) {
+			return false
+		}
+		if skip > 0 {
+			skip--
+			return false
+		}
+		ids = append(ids, id)
+		return len(ids) == size
+	})
+
+	for _, id := range ids {
+		if wrp := Get[T](r.db, id); wrp != nil {
+			wrps = append(wrps, wrp)
+		}
+	}
+	return wrps, nil
+}
+
+// filter returns the objects accessible by the user among the given ones.
+func (r Repository[T]) filter(wrps []*ObjWrapper[T]) []*ObjWrapper[T] {
+	var accessible []*ObjWrapper[T]
+	for _, wrp := range wrps {
+		if wrp != nil && r.canAccess(wrp.ID) {
+			accessible = append(accessible, wrp)
+		}
+	}
+	return accessible
+}
@@ -39,7 +39,17 @@ func (s *service) GetAccessManager() *AccessManager {
 
 func (s *service) IsFirstLaunch() bool {
 	// Check to see if a user has the "admin" role.
-	return len(VisitWrp[Role, User](s.accessManager.DefaultRoles["admin"])) == 0
+	return len(UsersOfRole(*s.store.DB, s.accessManager.DefaultRoles["admin"].ID)) == 0
+}
+
+// LoadFixtures loads the fixtures of the project (see storage.LoadFixtures). The roles of
+// the configuration are referenced by their name (e.g. Role: admin).
+func (s *service) LoadFixtures() error {
+	symbols := map[string]string{}
+	for name, role := range s.accessManager.DefaultRoles {
+		symbols["Role/"+name] = role.ID
+	}
+	return LoadFixtures(*s.store.DB, symbols)
 }
 
 // checkAppToken if is the first launch, the initialization token will be generated and write in file.
@@ -142,10 +152,11 @@ func (m AccessManager) CheckAccessForRoute(userId string, page string) bool {
 	if userId == "" {
 		userRoleWrp = GetApiV1().GetAccessManager().DefaultRoles["none"]
 	} else {
-		userRoleWrp = AllFromLink[User, Role](db, userId)[0]
+		userRoleWrp = RoleOfUser(db, userId)
 	}
 
-	return m.RulesRoutes[page] != nil && userRoleWrp.Value.Level >= m.RulesRoutes[page].Value.Level
+	return m.RulesRoutes[page] != nil && userRoleWrp != nil &&
+		userRoleWrp.Value.Level >= m.RulesRoutes[page].Value.Level
 }
 
 func CheckAccessForTable[T IObject](userId string, idOfT string) bool {
@@ -154,19 +165,36 @@ func CheckAccessForTable[T IObject](userId string, idOfT string) bool {
 	m := GetApiV1().GetAccessManager()
 
 	ttn := TableName[T]()
-	userRoleWrp := AllFromLink[User, Role](db, userId)[0]
+	userRoleWrp := RoleOfUser(db, userId)
 
-	if m.RulesTables[ttn] == nil {
+	if m.RulesTables[ttn] == nil || userRoleWrp == nil {
 		return false
 	}
 
 	if userRoleWrp.Value.Level > m.RulesTables[ttn].Value.Level {
 		return true
 	} else if userRoleWrp.Value.Level == m.RulesTables[ttn].Value.Level {
-		return len(Visit[User, T](db, idOfT)) > 0
+		// At the same level, only the users linked to the object by a relation declared
+		// in the project could access it.
+		owners := OwnersOf(db, ttn, idOfT)
+		return Find(&owners, func(id string) bool { return id == userId })
 	}
 
 	return false
 }
 
+// CheckLevelForTable reports whether the role of the user reaches the level required
+// by the access rules of the table, regardless of the objects it owns.
+func CheckLevelForTable[T IObject](userId string) bool {
+
+	db := *GetApiV1().GetStore().DB
+	m := GetApiV1().GetAccessManager()
+
+	ttn := TableName[T]()
+	userRoleWrp := RoleOfUser(db, userId)
+
+	return m.RulesTables[ttn] != nil && userRoleWrp != nil &&
+		userRoleWrp.Value.Level >= m.RulesTables[ttn].Value.Level
+}
+
 // endregion
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"errors"
+	"github.com/dgraph-io/badger"
+	"io"
+	"os"
+)
+
+// maxPendingWrites bounds the memory used by Restore while loading a backup.
+const maxPendingWrites = 256
+
+// Backup writes a full backup of the database to w with the streaming backup of Badger.
+// The database is opened by this function: the application must not be running.
+func Backup(w io.Writer) error {
+	db, err := badger.Open(badger.DefaultOptions(DbDirPath))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Backup(w, 0)
+	return err
+}
+
+// Restore loads a backup made by Backup in a new database. It fails when the database
+// directory already holds data so that a restore never merges two databases.
+func Restore(r io.Reader) error {
+	entries, err := os.ReadDir(DbDirPath)
+	if err == nil && len(entries) > 0 {
+		return errors.New("the database directory " + DbDirPath + " is not empty")
+	}
+
+	db, err := badger.Open(badger.DefaultOptions(DbDirPath))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.Load(r, maxPendingWrites)
+}
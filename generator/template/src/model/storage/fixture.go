@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	. "github.com/Phosmachina/FluentKV/reldb"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+const fixturePrefix = "fixture"
+
+var FixturesDirPath = "fixtures"
+
+type fixture struct {
+	table     string
+	name      string
+	data      []byte
+	relations map[string][]string
+}
+
+func (f fixture) symbol() string { return f.table + "/" + f.name }
+
+// LoadFixtures inserts the objects declared in the YAML documents of the fixtures
+// folder. Each document maps a storage type to its fixtures by symbolic name:
+//
+//	User:
+//	  alice:
+//	    Email: alice@example.com
+//	    Password: !bcrypt alice
+//	    Role: admin
+//
+// Relations are given by the symbolic names of their targets (a list for relations to
+// many objects). symbols gives the ids of objects created elsewhere (e.g. "Role/admin").
+// Loading is idempotent: the id of each fixture is recorded in the database and a
+// fixture whose object still exists is skipped.
+func LoadFixtures(db IRelationalDB, symbols map[string]string) error {
+
+	fixtures, err := readFixtures()
+	if err != nil {
+		return err
+	}
+
+	var created []fixture
+	for _, f := range fixtures {
+		if raw, ok := db.RawGet(fixturePrefix, f.symbol()); ok &&
+			fixtureExists(db, f.table, string(raw)) {
+			symbols[f.symbol()] = string(raw)
+			continue
+		}
+
+		id, err := insertFixture(db, f.table, f.data)
+		if err != nil {
+			return fmt.Errorf("fixture %s: %w", f.symbol(), err)
+		}
+		db.RawSet(fixturePrefix, f.symbol(), []byte(id))
+		symbols[f.symbol()] = id
+		created = append(created, f)
+	}
+
+	for _, f := range created {
+		for relation, names := range f.relations {
+			target := relationTargets[f.table][relation]
+			for _, name := range names {
+				targetId, ok := symbols[target+"/"+name]
+				if !ok {
+					return fmt.Errorf("fixture %s: unknown %s %s", f.symbol(), target, name)
+				}
+				err := linkFixture(db, f.table, relation, symbols[f.symbol()], targetId)
+				if err != nil {
+					return fmt.Errorf("fixture %s: %w", f.symbol(), err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// readFixtures reads the fixtures of the YAML documents, sorted by file name, in their
+// declaration order.
+func readFixtures() ([]fixture, error) {
+
+	paths, err := filepath.Glob(filepath.Join(FixturesDirPath, "*.y*ml"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(paths)
+
+	var fixtures []fixture
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var document yaml.Node
+		if err := yaml.Unmarshal(content, &document); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if len(document.Content) == 0 {
+			continue
+		}
+
+		tables := document.Content[0]
+		for i := 0; i+1 < len(tables.Content); i += 2 {
+			table, objects := tables.Content[i].Value, tables.Content[i+1]
+			if _, ok := relationTargets[table]; !ok {
+				return nil, fmt.Errorf("%s: unknown storage type %s", path, table)
+			}
+
+			for j := 0; j+1 < len(objects.Content); j += 2 {
+				f, err := newFixture(table, objects.Content[j].Value, objects.Content[j+1])
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", path, err)
+				}
+				fixtures = append(fixtures, f)
+			}
+		}
+	}
+
+	return fixtures, nil
+}
+
+// newFixture splits the relations from the attributes of the fixture, hashes the values
+// tagged !bcrypt and encodes the attributes in JSON to decode them in the storage type
+// ([]byte attributes are given in base64).
+func newFixture(table string, name string, node *yaml.Node) (fixture, error) {
+
+	f := fixture{table: table, name: name, relations: map[string][]string{}}
+	attributes := &yaml.Node{Kind: yaml.MappingNode}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		if _, ok := relationTargets[table][key.Value]; ok {
+			var names []string
+			if value.Kind == yaml.SequenceNode {
+				if err := value.Decode(&names); err != nil {
+					return f, err
+				}
+			} else {
+				names = []string{value.Value}
+			}
+			f.relations[key.Value] = names
+			continue
+		}
+
+		if value.Tag == "!bcrypt" {
+			hash, err := bcrypt.GenerateFromPassword([]byte(value.Value), bcrypt.DefaultCost)
+			if err != nil {
+				return f, err
+			}
+			value = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str",
+				Value: base64.StdEncoding.EncodeToString(hash)}
+		}
+		attributes.Content = append(attributes.Content, key, value)
+	}
+
+	var values map[string]any
+	if err := attributes.Decode(&values); err != nil {
+		return f, fmt.Errorf("fixture %s/%s: %w", table, name, err)
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return f, fmt.Errorf("fixture %s/%s: %w", table, name, err)
+	}
+	f.data = data
+
+	return f, nil
+}
+
+// insertFixtureOf decodes the attributes of a fixture in the storage type and inserts it.
+func insertFixtureOf[T IObject](db IRelationalDB, data []byte, obj T,
+	insert func(db IRelationalDB, obj T) (*ObjWrapper[T], error)) (string, error) {
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&obj); err != nil {
+		return "", err
+	}
+	wrp, err := insert(db, obj)
+	if err != nil {
+		return "", err
+	}
+	return wrp.ID, nil
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/Phosmachina/FluentKV/reldb"
+	"log"
+	"sync"
+)
+
+const (
+	// indexesKey records, next to the schema version, the IndexedAttributes for which the
+	// indexes of the database were built.
+	indexesKey = "indexes"
+	// indexKeyPrefix starts the Badger keys of all indexes (see indexPrefix).
+	indexKeyPrefix = "index#"
+)
+
+var (
+	ErrorNotFound  = errors.New("ErrorNotFound")
+	ErrorNotUnique = errors.New("ErrorNotUnique")
+
+	// indexLock serializes the writes of objects having indexed attributes, so that a
+	// unique value could not be taken twice between its check and its insertion.
+	indexLock = &sync.Mutex{}
+)
+
+// UniqueError is returned when an object would share the value of a unique attribute
+// with another object of its table. It matches ErrorNotUnique with errors.Is and keeps
+// its message so it is localized as other errors.
+type UniqueError struct {
+	Table     string
+	Attribute string
+}
+
+func (e UniqueError) Error() string        { return ErrorNotUnique.Error() }
+func (e UniqueError) Is(target error) bool { return target == ErrorNotUnique }
+
+// indexPrefix is the prefix of the Badger keys recording the ids of the objects of the
+// table having the given value for the attribute. The value is quoted so that the
+// prefix of a value never matches a longer one.
+func indexPrefix(table string, attribute string, value any) string {
+	return fmt.Sprintf("%s%s#%s=%q", indexKeyPrefix, table, attribute, fmt.Sprint(value))
+}
+
+// clearIndexes removes the keys of all indexes, with the ones of attributes no longer
+// indexed and the ones of values changed without the generated helpers.
+func clearIndexes(db IRelationalDB) {
+	var keys []string
+	db.RawIterKey(indexKeyPrefix, func(key string) bool {
+		keys = append(keys, key)
+		return false
+	})
+	for _, key := range keys {
+		db.RawDelete(indexKeyPrefix, key)
+	}
+}
+
+// CheckIndexes rebuilds the indexes when the indexed attributes differ from the ones
+// recorded in the database: for a database created before its attributes were indexed
+// (or before its schema version was recorded) and when an attribute becomes indexed or
+// unique. Objects stored without their index would not be found by the finders, and
+// their unique values could be taken again.
+func CheckIndexes(db IRelationalDB) {
+	if raw, ok := db.RawGet(schemaPrefix, indexesKey); ok && string(raw) == IndexedAttributes {
+		return
+	}
+	RebuildIndexes(db)
+	log.Print("Indexes rebuilt for the indexed attributes: ", IndexedAttributes)
+}
+
+// FindIndexed returns the ids of the objects of the table having the given value for
+// the indexed attribute.
+func FindIndexed(db IRelationalDB, table string, attribute string, value any) []string {
+	var ids []string
+	db.RawIterKey(indexPrefix(table, attribute, value), func(key string) bool {
+		ids = append(ids, key)
+		return false
+	})
+	return ids
+}
+
+func addIndex(db IRelationalDB, table string, attribute string, value any, id string) {
+	db.RawSet(indexPrefix(table, attribute, value), id, nil)
+}
+
+func removeIndex(db IRelationalDB, table string, attribute string, value any, id string) {
+	db.RawDelete(indexPrefix(table, attribute, value), id)
+}
+
+// checkUnique returns a UniqueError when another object than the one with the given id
+// (empty for a new object) has the value for the unique attribute.
+func checkUnique(db IRelationalDB, table string, attribute string, value any, id string) error {
+	for _, other := range FindIndexed(db, table, attribute, value) {
+		if other != id {
+			return UniqueError{Table: table, Attribute: attribute}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	. "github.com/Phosmachina/FluentKV/reldb"
+	"github.com/dgraph-io/badger"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+)
+
+const (
+	schemaPrefix     = "schema"
+	schemaVersionKey = "version"
+
+	// baselineSchemaVersion is the version of the first schema snapshot, given to a
+	// database which holds objects but predates the recording of its schema version.
+	baselineSchemaVersion = 1
+)
+
+var migrations []Migration
+
+// Migration converts the records stored with the previous version of the storage types
+// to its version. Migrations are generated as skeletons in the migrations folder when
+// storage types change in a way gob could not decode.
+type Migration struct {
+	Version int
+	Name    string
+	Apply   func(db IRelationalDB) error
+}
+
+// RegisterMigration makes a migration available to Migrate. It is called by the init
+// function of each migration file.
+func RegisterMigration(version int, name string, apply func(db IRelationalDB) error) {
+	migrations = append(migrations, Migration{Version: version, Name: name, Apply: apply})
+	slices.SortFunc(migrations, func(a, b Migration) int { return a.Version - b.Version })
+}
+
+// StoredSchemaVersion returns the version of the storage types recorded in the database.
+// A database without version is new, and recorded at the current SchemaVersion, or
+// predates schema tracking when it holds objects, and is recorded at the baseline
+// version so that every migration is applied to it.
+func StoredSchemaVersion(db IRelationalDB) int {
+	raw, ok := db.RawGet(schemaPrefix, schemaVersionKey)
+	if !ok {
+		version := SchemaVersion
+		if hasObjects(db) {
+			version = baselineSchemaVersion
+		}
+		setSchemaVersion(db, version)
+		return version
+	}
+	version, err := strconv.Atoi(string(raw))
+	if err != nil {
+		log.Fatal("Invalid schema version recorded in the database.")
+	}
+	return version
+}
+
+func setSchemaVersion(db IRelationalDB, version int) {
+	db.RawSet(schemaPrefix, schemaVersionKey, []byte(strconv.Itoa(version)))
+}
+
+// PendingMigrations returns, in order, the migrations not yet applied to the database.
+func PendingMigrations(db IRelationalDB) []Migration {
+	stored := StoredSchemaVersion(db)
+
+	var pending []Migration
+	for _, migration := range migrations {
+		if migration.Version > stored && migration.Version <= SchemaVersion {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+// CheckSchema stops the application when the database is not at the SchemaVersion: its
+// records could not be decoded with the current storage types.
+func CheckSchema(db IRelationalDB) {
+	stored := StoredSchemaVersion(db)
+	if stored > SchemaVersion {
+		log.Fatalf("The database schema (v%d) is newer than the application (v%d).",
+			stored, SchemaVersion)
+	}
+	if stored < SchemaVersion {
+		log.Fatalf("The database schema (v%d) must be migrated to v%d: run `db migrate`.",
+			stored, SchemaVersion)
+	}
+}
+
+// MigrateStep applies the next pending migration, then rebuilds the indexes since the
+// migration may change indexed values without the generated helpers. It is run by the
+// db migrate command on a staging copy of the database (see CommitStaging).
+func MigrateStep(db IRelationalDB) error {
+
+	stored := StoredSchemaVersion(db)
+	if stored >= SchemaVersion {
+		return nil
+	}
+	pending := PendingMigrations(db)
+	if len(pending) == 0 || pending[0].Version != stored+1 {
+		return fmt.Errorf("missing migration for the schema v%d", stored+1)
+	}
+
+	migration := pending[0]
+	if err := migration.Apply(db); err != nil {
+		return fmt.Errorf("migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+	setSchemaVersion(db, migration.Version)
+	RebuildIndexes(db)
+	log.Printf("Migration %04d_%s applied to the staging database.",
+		migration.Version, migration.Name)
+
+	return nil
+}
+
+// CopyDatabase replaces the database at the given path by a copy of the database of the
+// application, streamed from a backup of Badger. The application must not be running.
+func CopyDatabase(path string) error {
+
+	if err := os.RemoveAll(path); err != nil {
+		return err
+	}
+	src, err := badger.Open(badger.DefaultOptions(DbDirPath))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := badger.Open(badger.DefaultOptions(path))
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	r, w := io.Pipe()
+	defer r.Close()
+	go func() {
+		_, err := src.Backup(w, 0)
+		w.CloseWithError(err)
+	}()
+
+	return dst.Load(r, maxPendingWrites)
+}
+
+// CommitStaging writes the differences between the staging database at the given path
+// and the database of the application in one Badger transaction: the migration applied
+// to the staging database is committed entirely or not at all. It reports whether there
+// was a difference. A migration changing too many records for one transaction fails
+// with badger.ErrTxnTooBig, the database unchanged.
+func CommitStaging(path string) (bool, error) {
+
+	staging, err := badger.Open(badger.DefaultOptions(path))
+	if err != nil {
+		return false, err
+	}
+	defer staging.Close()
+	db, err := badger.Open(badger.DefaultOptions(DbDirPath))
+	if err != nil {
+		return false, err
+	}
+	defer db.Close()
+
+	changes, err := diffDatabases(db, staging)
+	if err != nil || len(changes) == 0 {
+		return false, err
+	}
+
+	err = db.Update(func(txn *badger.Txn) error {
+		for _, change := range changes {
+			var err error
+			if change.isDeleted {
+				err = txn.Delete(change.key)
+			} else {
+				err = txn.Set(change.key, change.value)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, badger.ErrTxnTooBig) {
+		return false, fmt.Errorf("the migration changes too many records for one "+
+			"transaction: %w", err)
+	}
+
+	return err == nil, err
+}
+
+// keyChange is a key to set to its value, or to delete, to go from a database to another.
+type keyChange struct {
+	key       []byte
+	value     []byte
+	isDeleted bool
+}
+
+// diffDatabases returns the changes making the keys of the database from the ones of the
+// next database. Both are iterated in the order of their keys.
+func diffDatabases(db *badger.DB, next *badger.DB) ([]keyChange, error) {
+
+	var changes []keyChange
+	err := db.View(func(txn *badger.Txn) error {
+		return next.View(func(nextTxn *badger.Txn) error {
+
+			it := txn.NewIterator(badger.DefaultIteratorOptions)
+			defer it.Close()
+			nextIt := nextTxn.NewIterator(badger.DefaultIteratorOptions)
+			defer nextIt.Close()
+
+			it.Rewind()
+			nextIt.Rewind()
+			for it.Valid() || nextIt.Valid() {
+				order := -1
+				if !it.Valid() {
+					order = 1
+				} else if nextIt.Valid() {
+					order = bytes.Compare(it.Item().Key(), nextIt.Item().Key())
+				}
+
+				if order < 0 {
+					changes = append(changes,
+						keyChange{key: it.Item().KeyCopy(nil), isDeleted: true})
+					it.Next()
+					continue
+				}
+
+				value, err := nextIt.Item().ValueCopy(nil)
+				if err != nil {
+					return err
+				}
+				if order == 0 {
+					previous, err := it.Item().ValueCopy(nil)
+					if err != nil {
+						return err
+					}
+					it.Next()
+					if bytes.Equal(previous, value) {
+						nextIt.Next()
+						continue
+					}
+				}
+				changes = append(changes,
+					keyChange{key: nextIt.Item().KeyCopy(nil), value: value})
+				nextIt.Next()
+			}
+			return nil
+		})
+	})
+
+	return changes, err
+}
@@ -7,8 +7,8 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
-	"time"
 )
 
 var (
@@ -26,6 +26,7 @@ var (
 	CookieNameForSession = "session-id"
 	CookieNameForCSRF    = "csrf-token"
 	SessionKeyForUserId  = "user-id"
+	SessionKeyForLang    = "lang"
 )
 
 type Storage struct {
@@ -72,12 +73,11 @@ func (s Storage) LoadConfiguration() {
 	if err := yaml.Unmarshal(data, s.Config); err != nil {
 		log.Fatal(err)
 	}
-	s.Config.CurrentLang = DefaultLang
 }
 
-type SessionItem struct {
-	LastViewed time.Time
-	UA         string
+// IsLang reports whether the language is one of the languages of the application.
+func IsLang(lang string) bool {
+	return slices.Contains(Langs, lang)
 }
 
 type AccessRule struct {
@@ -0,0 +1,194 @@
+// noinspection DuplicatedCode
+
+//region I18N
+
+// The i18n generator writes a bundle by language in js/i18n/ (e.g. js/i18n/fr.js) with
+// the translations whose key is under the js_i18n_prefix of the project; the layout
+// loads the one of the page language. Translations follow the rules of the server: the
+// missing ones are the ones of the default language, plural forms use the CLDR plural
+// category of the count and named placeholders the ICU-style syntax.
+
+const PLURAL_SUFFIXES = Object.freeze({
+    zero: ["_zero"],
+    one: ["_one", "_singular"],
+    two: ["_two"],
+    few: ["_few"],
+    many: ["_many"],
+    other: ["_other", "_plural"],
+})
+
+/**
+ * Returns the translation of the key in the language of the page.
+ *
+ * When the first argument is an integer, the plural form of its CLDR category is used
+ * (e.g. day_one or day_other for the key day). The arguments replace the format verbs
+ * (%s, %d...) of the translation, or its named placeholders in their order of
+ * appearance; a single object gives the named placeholders by name.
+ *
+ * @param {string} key - The full key of the translation (e.g. js.network_error).
+ * @param {...any} args - The arguments of the translation.
+ * @returns {string} - The translation, otherwise "Key not found".
+ */
+function t(key, ...args) {
+
+    let bundle = window.i18nBundle || {lang: document.documentElement.lang, messages: {}}
+    let message
+
+    if (Number.isInteger(args[0])) {
+        let category = pluralCategory(bundle.lang, args[0])
+        message = [category, "other"]
+            .flatMap(c => PLURAL_SUFFIXES[c])
+            .map(suffix => bundle.messages[key + suffix])
+            .find(value => value !== undefined)
+    }
+    if (message === undefined) message = bundle.messages[key]
+    if (message === undefined) return "Key not found"
+
+    let names = placeholderNames(message)
+    if (names.length === 0) {
+        return formatVerbs(message, args)
+    }
+
+    let named = args.length === 1 && args[0] !== null && typeof args[0] === "object" ?
+        args[0] : Object.fromEntries(names.slice(0, args.length).map((name, i) => [name, args[i]]))
+    return formatMessage(bundle.lang, message, named)
+}
+
+/**
+ * Returns the CLDR plural category (zero, one, two, few, many or other) of the count in
+ * the language.
+ *
+ * @param {string} lang - The language, e.g. fr.
+ * @param {number} count - The count.
+ * @returns {string} - The plural category.
+ */
+function pluralCategory(lang, count) {
+    try {
+        return new Intl.PluralRules(lang).select(Math.abs(count))
+    } catch (e) {
+        return Math.abs(count) === 1 ? "one" : "other"
+    }
+}
+
+function formatVerbs(message, args) {
+    let i = 0
+    return message.replace(/%[-+# 0]*(\[\d+])?\d*(\.\d+)?[a-zA-Z%]/g, verb =>
+        verb === "%%" ? "%" : String(i < args.length ? args[i++] : verb))
+}
+
+function placeholderNames(message) {
+    let names = []
+    new IcuMessage(message, "", {}, names).text(null)
+    return names
+}
+
+function formatMessage(lang, message, args) {
+    return new IcuMessage(message, lang, args, []).text(null)
+}
+
+/**
+ * IcuMessage formats an ICU-style translation: {name} is replaced by the argument of the
+ * name, {name, plural, one {# day} other {# days}} picks the option of the plural
+ * category of the argument (or an exact =N option; # is the count) and
+ * {name, select, admin {...} other {...}} the option of the argument. An apostrophe
+ * quotes the braces and # which follow it, two apostrophes are an apostrophe.
+ */
+class IcuMessage {
+
+    constructor(src, lang, args, names) {
+        this.src = src
+        this.pos = 0
+        this.lang = lang
+        this.args = args
+        this.names = names
+    }
+
+    text(count) {
+        let out = ""
+        while (this.pos < this.src.length) {
+            let c = this.src[this.pos]
+            if (c === "}") {
+                return out
+            } else if (c === "{") {
+                this.pos++
+                out += this.argument()
+            } else if (c === "#" && count !== null) {
+                this.pos++
+                out += count
+            } else if (c === "'") {
+                out += this.quoted()
+            } else {
+                out += c
+                this.pos++
+            }
+        }
+        return out
+    }
+
+    quoted() {
+        this.pos++
+        if (this.pos >= this.src.length) return "'"
+        if (this.src[this.pos] === "'") {
+            this.pos++
+            return "'"
+        }
+        if (!"{}#".includes(this.src[this.pos])) return "'"
+
+        let end = this.src.indexOf("'", this.pos)
+        if (end === -1) end = this.src.length
+        let quoted = this.src.slice(this.pos, end)
+        this.pos = Math.min(end + 1, this.src.length)
+        return quoted
+    }
+
+    argument() {
+
+        let name = this.until(",}").trim()
+        if (!this.names.includes(name)) this.names.push(name)
+        let value = this.args[name]
+
+        if (this.pos >= this.src.length || this.src[this.pos] === "}") {
+            this.pos++
+            return value === undefined ? `{${name}}` : String(value)
+        }
+
+        this.pos++
+        let kind = this.until(",}").trim()
+        if (this.src[this.pos] === ",") this.pos++
+
+        let count = null
+        let selected = String(value)
+        if (kind === "plural") {
+            count = Number.isInteger(value) ? value : 0
+            selected = pluralCategory(this.lang, count)
+        }
+
+        let options = {}
+        while (this.pos < this.src.length) {
+            this.skipSpaces()
+            if (this.pos >= this.src.length || this.src[this.pos] === "}") break
+            let selector = this.until("{}").trim()
+            if (this.src[this.pos] !== "{") break
+            this.pos++
+            options[selector] = this.text(count)
+            this.pos++
+        }
+        this.pos++
+
+        if (count !== null && options[`=${count}`] !== undefined) return options[`=${count}`]
+        if (options[selected] !== undefined) return options[selected]
+        return options["other"] || ""
+    }
+
+    until(chars) {
+        let start = this.pos
+        while (this.pos < this.src.length && !chars.includes(this.src[this.pos])) this.pos++
+        return this.src.slice(start, this.pos)
+    }
+
+    skipSpaces() {
+        while (this.pos < this.src.length && " \t\n".includes(this.src[this.pos])) this.pos++
+    }
+}
+
+//endregion
@@ -63,7 +63,10 @@ const handleInput = (labels, idxLabel) => {
         }
     }
 
-    input.onchange = () => allVerify.forEach(verify => verify && verify())
+    input.onchange = () => {
+        clearFieldErrors(labels[idxLabel])
+        allVerify.forEach(verify => verify && verify())
+    }
 
     return labels[idxLabel]
 }
@@ -260,25 +263,36 @@ function atLeast8Char(value) {
 
 //region FETCH
 
+// The api* functions are generated in client.js from the controllers of the project.
+
 function initRequest() {
-    simpleFetch("/api/v1/activate/admin", mapFormValues(document.querySelector("form")))
+    apiV1ActivateAdmin(Object.fromEntries(mapFormValues(document.querySelector("form"))))
         .then(() => window.location = "/login")
-        .catch(() => document.querySelector("[name='token']").value = "")
+        .catch(error => {
+            notifyApiError(error)
+            document.querySelector("[name='token']").value = ""
+        })
 }
 
 function login() {
-    simpleFetch("/api/v1/login", mapFormValues(document.querySelector("form")))
+    apiV1Login(Object.fromEntries(mapFormValues(document.querySelector("form"))))
         .then(() => window.location = "/")
-        .catch(() => document.querySelector("[name='password']").value = "")
+        .catch(error => {
+            notifyApiError(error)
+            document.querySelector("[name='password']").value = ""
+        })
 }
 
 function adminLogin() {
     let map = mapFormValues(document.querySelector("form"))
     map.set("is_admin", true)
 
-    simpleFetch("/api/v1/login", map)
+    apiV1Login(Object.fromEntries(map))
         .then(() => window.location = "/admin")
-        .catch(() => document.querySelector("[name='password']").value = "")
+        .catch(error => {
+            notifyApiError(error)
+            document.querySelector("[name='password']").value = ""
+        })
 }
 
 //endregion
@@ -295,9 +309,9 @@ function toggleTheme() {
 
 function toggleLang(btn) {
     let lang = btn.querySelector("p").innerText;
-    simpleFetch("/api/v1/update/lang", new Map([["lang", lang]]))
+    apiV1UpdateLang({lang: lang})
         .then(() => document.location.reload())
-        .catch()
+        .catch(notifyApiError)
 }
 
 //endregion
@@ -305,48 +319,21 @@ function toggleLang(btn) {
 //region HELPERS
 
 /**
- * Makes an asynchronous HTTP POST request to the specified URL with a content payload and CSRF token.
+ * Notifies the user of an error raised by a call to the API. When the server rejected
+ * the request, its reason is shown in a notification and its field errors are attached
+ * to the matching inputs.
  *
- * @param {string} url - The URL to send the request to.
- * @param {Object} content - The payload of the request.
- * @returns {Promise} - A promise that resolves to the parsed JSON response from the server.
- * @throws {Error} - If the HTTP response status is not OK.
+ * @param {Error} error - The error raised by the call.
  */
-async function fetchRequest(url, content) {
-    const csrfToken = document.cookie
-        .split('; ')
-        .find(row => row.startsWith('csrf-token='))
-        .split('=')[1]
-
-    return fetch(url, {
-        method: 'POST',
-        headers: {
-            'Content-Type': 'application/json',
-            'charset': 'UTF-8',
-            'X-CSRF-Token': csrfToken
-        },
-        body: JSON.stringify(content)
-    }).then(response => {
-        return response.json()
-    })
-}
+function notifyApiError(error) {
+    if (!(error instanceof ApiError)) {
+        console.error(error)
+        newNotification(t("js.network_error"), NOTIFICATION_TYPE.ERROR)
+        return
+    }
 
-/**
- * Send a fetch request with the specified URL and payload.
- *
- * @param {string} url - The URL to send the fetch request to.
- * @param {Map} map - The map object containing the payload data.
- * @returns {Promise} A promise that resolves to the response of the fetch request. If the response has a reason property, an error is thrown with the reason.
- */
-function simpleFetch(url, map) {
-    return fetchRequest(url, Object.fromEntries(map))
-        .then(response => {
-            if (response.reason) {
-                newNotification(response.reason, NOTIFICATION_TYPE.ERROR)
-                throw new Error(`Server reject the request with reason: ${response.reason}`)
-            }
-            return response
-        })
+    showFieldErrors(error.fields)
+    newNotification(error.reason, NOTIFICATION_TYPE.ERROR)
 }
 
 /**
@@ -368,6 +355,41 @@ function mapFormValues(form) {
     return map
 }
 
+/**
+ * Attaches the field errors returned by the server to the advice group of the matching
+ * inputs. An input is matched when its name is the json name of the failing field.
+ *
+ * @param {Array<{field: string, tag: string, message: string}>} fields - The failing fields.
+ */
+function showFieldErrors(fields) {
+    fields.forEach(error => {
+        let input = document.querySelector(`[name='${error.field}']`)
+        let label = input && input.closest("label")
+        let adviceGroup = label && label.querySelector(".advice-group")
+        if (!adviceGroup) return
+
+        input.classList.remove("is-valid")
+        input.classList.add("is-error")
+
+        let adviceNode = adviceGroup.querySelector(`.advice-server-${error.tag}`)
+        if (!adviceNode) {
+            adviceNode = document.querySelector("#advice").content.cloneNode(true).querySelector("div")
+            adviceNode.classList.add("advice-server", `advice-server-${error.tag}`)
+            adviceGroup.appendChild(adviceNode)
+        }
+        adviceNode.innerHTML = error.message
+    })
+}
+
+/**
+ * Removes the advices added from a server response inside the given label.
+ *
+ * @param {HTMLElement} label - The label containing the advice group.
+ */
+function clearFieldErrors(label) {
+    label.querySelectorAll(".advice-server").forEach(advice => advice.remove())
+}
+
 function newNotification(msg, type) {
 
     let notification_group = document.querySelector("#notification-group")
@@ -0,0 +1,22 @@
+package i18n
+
+import "sync"
+
+// I18n stubs the translations of the runtime i18n package with the patterns set by the
+// tests.
+type I18n struct {
+	dic map[string]map[string]string
+	mu  sync.RWMutex
+}
+
+var instance = &I18n{dic: map[string]map[string]string{}}
+
+func GetInstance() *I18n { return instance }
+
+func (i *I18n) lookup(lang string, key string) (string, bool) {
+	if val, ok := i.dic[lang][key]; ok {
+		return val, true
+	}
+	val, ok := i.dic["en"][key]
+	return val, ok
+}
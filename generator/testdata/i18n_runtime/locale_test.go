@@ -0,0 +1,71 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+)
+
+func init() {
+	instance.dic["fr"] = map[string]string{
+		"locale.decimal":      ",",
+		"locale.group":        " ",
+		"locale.months":       "janvier,février,mars,avril,mai,juin,juillet,août,septembre,octobre,novembre,décembre",
+		"locale.months_short": "janv.,févr.,mars,avr.,mai,juin,juil.,août,sept.,oct.,nov.,déc.",
+		"locale.days":         "dimanche, lundi, mardi, mercredi, jeudi, vendredi, samedi",
+		"locale.days_short":   "dim.",
+	}
+}
+
+func TestFormatterNumber(t *testing.T) {
+
+	tests := []struct {
+		lang     string
+		value    float64
+		decimals int
+		want     string
+	}{
+		{"en", 0, 0, "0"},
+		{"en", 999, 0, "999"},
+		{"en", 1000, 0, "1,000"},
+		{"en", 1234567.891, 2, "1,234,567.89"},
+		{"en", -1234.5, 1, "-1,234.5"},
+		{"en", -0.001, 2, "0.00"},
+		{"en", 0.5, 0, "0"},
+		{"en", 1.5, 0, "2"},
+		{"fr", 1234567.891, 2, "1 234 567,89"},
+		{"fr", -12.5, 1, "-12,5"},
+		{"de", 1234.5, 1, "1,234.5"},
+	}
+	for _, test := range tests {
+		got := FormatterIn(test.lang).Number(test.value, test.decimals)
+		if got != test.want {
+			t.Errorf("%s %v %d: got %q, want %q", test.lang, test.value, test.decimals,
+				got, test.want)
+		}
+	}
+}
+
+func TestFormatterLayout(t *testing.T) {
+
+	date := time.Date(2024, time.August, 5, 14, 3, 0, 0, time.UTC)
+
+	tests := []struct {
+		lang   string
+		layout string
+		want   string
+	}{
+		{"en", "Monday, January 2, 2006", "Monday, August 5, 2024"},
+		{"en", "Mon Jan 2", "Mon Aug 5"},
+		{"en", "02/01/2006 15:04", "05/08/2024 14:03"},
+		{"fr", "Monday 2 January 2006", "lundi 5 août 2024"},
+		{"fr", "Mon 2 Jan", "Mon 5 août"},
+		{"fr", "January", "août"},
+		{"fr", "2006-01-02", "2024-08-05"},
+		{"fr", "", ""},
+	}
+	for _, test := range tests {
+		if got := FormatterIn(test.lang).layout(date, test.layout); got != test.want {
+			t.Errorf("%s %q: got %q, want %q", test.lang, test.layout, got, test.want)
+		}
+	}
+}
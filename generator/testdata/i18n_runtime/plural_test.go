@@ -0,0 +1,77 @@
+package i18n
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPluralCategory(t *testing.T) {
+
+	tests := []struct {
+		lang  string
+		count int
+		want  string
+	}{
+		{"en", 1, "one"},
+		{"en", 0, "other"},
+		{"en", -1, "one"},
+		{"xx", 1, "one"},
+		{"xx", 2, "other"},
+		{"fr", 0, "one"},
+		{"fr", 2, "other"},
+		{"fr-CA", 0, "one"},
+		{"pt_BR", 1, "one"},
+		{"ru", 1, "one"},
+		{"ru", 3, "few"},
+		{"ru", 5, "many"},
+		{"ru", 11, "many"},
+		{"ru", 21, "one"},
+		{"pl", 22, "few"},
+		{"pl", 12, "many"},
+		{"ar", 0, "zero"},
+		{"ar", 2, "two"},
+		{"ar", 103, "few"},
+		{"ar", 11, "many"},
+		{"ar", 100, "other"},
+		{"ro", 0, "few"},
+		{"ro", 20, "other"},
+		{"lv", 10, "zero"},
+		{"lv", 21, "one"},
+		{"ja", 1, "other"},
+	}
+	for _, test := range tests {
+		if got := PluralCategory(test.lang, test.count); got != test.want {
+			t.Errorf("%s %d: got %s, want %s", test.lang, test.count, got, test.want)
+		}
+	}
+}
+
+// TestPluralRulesCategories compares the rules to pluralCategories, the categories
+// expected by the i18n check of the generator.
+func TestPluralRulesCategories(t *testing.T) {
+
+	for lang := range pluralRules {
+		if _, ok := pluralCategories[lang]; !ok {
+			t.Errorf("%s: no categories in the generator", lang)
+		}
+	}
+
+	for lang, want := range pluralCategories {
+		if _, ok := pluralRules[lang]; !ok && lang != "en" {
+			t.Errorf("%s: no rule in the runtime", lang)
+			continue
+		}
+		var got []string
+		for _, category := range []string{"zero", "one", "two", "few", "many", "other"} {
+			for n := 0; n <= 1000; n++ {
+				if PluralCategory(lang, n) == category {
+					got = append(got, category)
+					break
+				}
+			}
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: got categories %v, want %v", lang, got, want)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+function notify(count) {
+    newNotification(t("js.network_error"))
+    newNotification(t('js.items', count))
+    newNotification(t("js.nope"))
+    newNotification(t("view.index.title"))
+    newNotification(format("js.other"))
+}
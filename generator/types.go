@@ -1,5 +1,11 @@
 package generator
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
 type ViewTypes struct {
 	Types        []VectraType[SimpleAttribute] `yaml:"types"`
 	Constructors []ViewTypeConstructor         `yaml:"constructors"`
@@ -13,13 +19,86 @@ type ViewTypeConstructor struct {
 }
 
 type VectraType[T any] struct {
-	Name       string `yaml:"name"`
-	Attributes []T    `yaml:"attributes"`
+	Name       string     `yaml:"name"`
+	Attributes []T        `yaml:"attributes"`
+	Relations  []Relation `yaml:"relations,omitempty"`
+
+	// From names the storage type a view or exchange type is derived from (see
+	// resolveMappings): Include and Exclude select the attributes taken from it.
+	From         string   `yaml:"from,omitempty"`
+	Include      []string `yaml:"include,omitempty"`
+	Exclude      []string `yaml:"exclude,omitempty"`
+	MappedFields []string `yaml:"-"`
+}
+
+// Relation links a storage type to another one with FluentKV links. Kind is read from
+// the declaring type to the target: one_to_one, one_to_many, many_to_one or
+// many_to_many. With CascadeDelete, deleting the declaring object deletes its targets.
+type Relation struct {
+	Name          string `yaml:"name"`
+	Target        string `yaml:"target"`
+	Kind          string `yaml:"kind"`
+	CascadeDelete bool   `yaml:"cascade_delete,omitempty"`
+}
+
+// IsSourceMany reports whether a target could be linked to many declaring objects.
+func (r Relation) IsSourceMany() bool {
+	return strings.HasPrefix(r.Kind, "many_")
+}
+
+// IsTargetMany reports whether a declaring object could be linked to many targets.
+func (r Relation) IsTargetMany() bool {
+	return strings.HasSuffix(r.Kind, "_many")
 }
 
+// SimpleAttribute is an attribute of a type. A sensitive attribute (a password or its
+// hash) is redacted from the String, ToString and JSON forms of the storage and exchange
+// types, never mapped and skipped by mod tags.
 type SimpleAttribute struct {
-	Name string `yaml:"name"`
-	Type string `yaml:"type"`
+	Name        string `yaml:"name"`
+	Type        string `yaml:"type"`
+	IsSensitive bool   `yaml:"sensitive,omitempty"`
+}
+
+func (a SimpleAttribute) sensitive() bool {
+	return a.IsSensitive
+}
+
+// StorageAttribute is an attribute of a storage type. An indexed attribute gets a
+// FindBy finder in the repository of its type, backed by dedicated Badger keys. A
+// unique attribute is indexed and could not share its value between two objects.
+type StorageAttribute struct {
+	SimpleAttribute `yaml:",inline"`
+	IsIndexed       bool `yaml:"indexed,omitempty"`
+	IsUnique        bool `yaml:"unique,omitempty"`
+}
+
+func (a StorageAttribute) HasIndex() bool {
+	return a.IsIndexed || a.IsUnique
+}
+
+// HasIndex tells if one of the attributes of the storage type is indexed.
+func (t VectraType[T]) HasIndex() bool {
+	return slices.ContainsFunc(t.Attributes, func(attribute T) bool {
+		a, ok := any(attribute).(StorageAttribute)
+		return ok && a.HasIndex()
+	})
+}
+
+// HasSensitive tells if one of the attributes of the type is sensitive.
+func (t VectraType[T]) HasSensitive() bool {
+	return slices.ContainsFunc(t.Attributes, func(attribute T) bool {
+		a, ok := any(attribute).(interface{ sensitive() bool })
+		return ok && a.sensitive()
+	})
+}
+
+// HasUnique tells if one of the attributes of the storage type is unique.
+func (t VectraType[T]) HasUnique() bool {
+	return slices.ContainsFunc(t.Attributes, func(attribute T) bool {
+		a, ok := any(attribute).(StorageAttribute)
+		return ok && a.IsUnique
+	})
 }
 
 type AttributeWithTag struct {
@@ -44,16 +123,25 @@ func NewTypes(cfg *Vectra) *Generator {
 		[]string{
 			"Configuration",
 			"DefaultLang",
+			"WatcherConfig.I18nConfig",
+			"Enums",
+			"ValueTypes",
 			"StorageTypes",
 			"ViewTypes",
 		},
 		Report{
 			Files: []SourceFile{
 				NewSourceFile("src/model/storage/configuration.go.tmpl", FullGen),
+				NewSourceFile("src/model/storage/values.go.tmpl", FullGen),
 				NewSourceFile("src/model/storage/types.go.tmpl", FullGen),
+				NewSourceFile("src/model/storage/export.go.tmpl", FullGen),
+				NewSourceFile("src/model/storage/fixtures.go.tmpl", FullGen),
+				NewSourceFile("src/model/service/repositories.go.tmpl", FullGen),
 				NewSourceFile("src/view/go/view.go.tmpl", Skeleton),
+				NewSourceFile("src/view/pug/shared/types_completion_variables.pug.tmpl",
+					FullGen),
 			},
-			Version: 2,
+			Version: 3,
 		}, cfg)
 
 	n := &Types{}
@@ -65,16 +153,113 @@ func NewTypes(cfg *Vectra) *Generator {
 
 func (i *Types) Generate() {
 
+	if err := i.vectra.checkTypes(); err != nil {
+		i.cancel("Invalid types", err)
+		return
+	}
+
+	if err := i.vectra.resolveMappings(); err != nil {
+		i.cancel("Invalid mappings", err)
+		return
+	}
+
+	if err := checkRelations(i.vectra.StorageTypes); err != nil {
+		i.cancel("Invalid relations", err)
+		return
+	}
+
+	if err := i.vectra.checkPugVariables(); err != nil {
+		i.cancel("Unknown page context fields", err)
+		return
+	}
+
+	schema, err := i.vectra.updateSchema()
+	if err != nil {
+		i.cancel("Failed to update the storage schema", err)
+		return
+	}
+
 	i.vectra.ViewTypes.Bodies = extractFunctionBody(
 		i.vectra.ProjectPath + "/src/view/go/view.go")
 
+	ctx, constructors := i.vectra.pugCompletion()
+
 	i.Generator.Generate(map[string]any{
 		"Configuration": map[string]any{
 			"IsDev":         !i.vectra.isProdGen,
 			"DefaultLang":   i.vectra.DefaultLang,
+			"WatchI18n":     i.vectra.WatcherConfig.I18nConfig.ReloadApp,
 			"Configuration": i.vectra.Configuration,
 		},
-		"StorageTypes": i.vectra.StorageTypes,
-		"ViewTypes":    i.vectra.ViewTypes,
+		"Enums":         i.vectra.Enums,
+		"ValueTypes":    i.vectra.ValueTypes,
+		"StorageTypes":  i.vectra.StorageTypes,
+		"SchemaVersion": schema.Version,
+		"ViewTypes":     i.vectra.ViewTypes,
+		"types_completion_variables": map[string]any{
+			"Ctx":          ctx,
+			"Constructors": constructors,
+		},
 	})
 }
+
+// checkRelations validates the relations between storage types. FluentKV links only
+// know the tables they join, so a pair of types could have only one relation, and
+// cascading deletions must not loop.
+func checkRelations(types []VectraType[StorageAttribute]) error {
+
+	kinds := []string{"one_to_one", "one_to_many", "many_to_one", "many_to_many"}
+	names := map[string]bool{}
+	for _, t := range types {
+		names[t.Name] = true
+	}
+
+	pairs := map[string]bool{}
+	cascades := map[string][]string{}
+
+	for _, t := range types {
+		for _, r := range t.Relations {
+			if !names[r.Target] {
+				return fmt.Errorf("the target %s of relation %s.%s is not a storage type",
+					r.Target, t.Name, r.Name)
+			}
+			if !slices.Contains(kinds, r.Kind) {
+				return fmt.Errorf("the kind %s of relation %s.%s is not one of %s",
+					r.Kind, t.Name, r.Name, strings.Join(kinds, ", "))
+			}
+
+			pair := []string{t.Name, r.Target}
+			slices.Sort(pair)
+			key := strings.Join(pair, "-")
+			if pairs[key] {
+				return fmt.Errorf("%s and %s have more than one relation", t.Name, r.Target)
+			}
+			pairs[key] = true
+
+			if r.CascadeDelete {
+				cascades[t.Name] = append(cascades[t.Name], r.Target)
+			}
+		}
+	}
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		if slices.Contains(path, name) {
+			return fmt.Errorf("cascading deletions loop: %s",
+				strings.Join(append(path, name), " → "))
+		}
+		for _, target := range cascades[name] {
+			if err := visit(target, append(path, name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for name := range cascades {
+		if err := visit(name, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
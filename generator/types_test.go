@@ -0,0 +1,53 @@
+package generator
+
+import "testing"
+
+func TestCheckRelations(t *testing.T) {
+
+	storageType := func(name string, relations ...Relation) VectraType[StorageAttribute] {
+		return VectraType[StorageAttribute]{Name: name, Relations: relations}
+	}
+
+	tests := []struct {
+		name    string
+		types   []VectraType[StorageAttribute]
+		wantErr bool
+	}{
+		{"valid", []VectraType[StorageAttribute]{
+			storageType("User", Relation{Name: "Role", Target: "Role", Kind: "many_to_one"}),
+			storageType("Role"),
+		}, false},
+		{"cascade chain", []VectraType[StorageAttribute]{
+			storageType("User", Relation{Name: "Post", Target: "Post", Kind: "one_to_many",
+				CascadeDelete: true}),
+			storageType("Post", Relation{Name: "Comment", Target: "Comment",
+				Kind: "one_to_many", CascadeDelete: true}),
+			storageType("Comment"),
+		}, false},
+		{"unknown target", []VectraType[StorageAttribute]{
+			storageType("User", Relation{Name: "Role", Target: "Role", Kind: "many_to_one"}),
+		}, true},
+		{"unknown kind", []VectraType[StorageAttribute]{
+			storageType("User", Relation{Name: "Role", Target: "Role", Kind: "one_to_few"}),
+			storageType("Role"),
+		}, true},
+		{"two relations of a pair", []VectraType[StorageAttribute]{
+			storageType("User", Relation{Name: "Role", Target: "Role", Kind: "many_to_one"}),
+			storageType("Role", Relation{Name: "Owner", Target: "User", Kind: "one_to_one"}),
+		}, true},
+		{"cascade loop", []VectraType[StorageAttribute]{
+			storageType("User", Relation{Name: "Post", Target: "Post", Kind: "one_to_many",
+				CascadeDelete: true}),
+			storageType("Post", Relation{Name: "Comment", Target: "Comment",
+				Kind: "one_to_many", CascadeDelete: true}),
+			storageType("Comment", Relation{Name: "Author", Target: "User",
+				Kind: "many_to_one", CascadeDelete: true}),
+		}, true},
+	}
+	for _, test := range tests {
+		err := checkRelations(test.types)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error: %t", test.name, err, test.wantErr)
+		}
+	}
+}
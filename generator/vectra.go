@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"gopkg.in/yaml.v3"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 )
 
@@ -27,13 +29,19 @@ var (
 				Watcher{IsEnabled: true},
 			},
 			I18nConfig: I18nConfig{
-				Watcher{IsEnabled: true},
+				Watcher: Watcher{IsEnabled: true},
 			},
 		},
 		SpriteConfig: SpriteConfig{
 			SvgFolderPath:   "static/svg",
 			OutputSpriteSvg: "static/svg/sprite",
 		},
+		OpenApiConfig: OpenApiConfig{
+			Version: "1.0.0",
+		},
+		JsI18nConfig: JsI18nConfig{
+			Prefix: "js",
+		},
 		NetConfDev: NetworkConfig{
 			Domain: "localhost",
 			Port:   8100,
@@ -44,41 +52,70 @@ var (
 		WithI18nExample:      true,
 		WithSassExample:      true,
 		WithPugExample:       true,
-		StorageTypes: []VectraType[SimpleAttribute]{
+		Roles: map[string]int{
+			"none":       0,
+			"registered": 1,
+			"admin":      2,
+		},
+		ValueTypes: []VectraType[SimpleAttribute]{
 			{
-				"Role",
-				[]SimpleAttribute{
-					{Name: "Name", Type: "string"},
-					{Name: "Level", Type: "int"}},
+				Name: "SessionItem",
+				Attributes: []SimpleAttribute{
+					{Name: "LastViewed", Type: "time.Time"},
+					{Name: "UA", Type: "string"}},
+			},
+		},
+		StorageTypes: []VectraType[StorageAttribute]{
+			{
+				Name: "Role",
+				Attributes: []StorageAttribute{
+					{SimpleAttribute{Name: "Name", Type: "string"}, false, false},
+					{SimpleAttribute{Name: "Level", Type: "int"}, false, false}},
 			},
 			{
-				"User",
-				[]SimpleAttribute{
-					{Name: "IsActivated", Type: "bool"},
-					{Name: "Password", Type: "[]byte"},
-					{Name: "Firstname", Type: "string"},
-					{Name: "Lastname", Type: "string"},
-					{Name: "Email", Type: "string"},
-					{Name: "Sessions", Type: "map[string]SessionItem"}},
+				Name: "User",
+				Attributes: []StorageAttribute{
+					{SimpleAttribute{Name: "IsActivated", Type: "bool"}, false, false},
+					{SimpleAttribute{Name: "Password", Type: "[]byte", IsSensitive: true}, false, false},
+					{SimpleAttribute{Name: "Firstname", Type: "string"}, false, false},
+					{SimpleAttribute{Name: "Lastname", Type: "string"}, false, false},
+					{SimpleAttribute{Name: "Email", Type: "string"}, false, true},
+					{SimpleAttribute{Name: "Lang", Type: "string"}, false, false},
+					{SimpleAttribute{Name: "Sessions", Type: "map[string]SessionItem"}, false, false}},
+				Relations: []Relation{
+					{Name: "Role", Target: "Role", Kind: "many_to_one"},
+				},
 			},
 		},
 		ViewTypes: ViewTypes{
 			Types: []VectraType[SimpleAttribute]{
 				{
-					"GlobalCtx",
-					[]SimpleAttribute{
+					Name: "GlobalCtx",
+					Attributes: []SimpleAttribute{
 						{Name: "IsDev", Type: "bool"},
 						{Name: "Domain", Type: "string"},
 						{Name: "Port", Type: "int"},
 						{Name: "TabTitle", Type: "string"},
 						{Name: "Lang", Type: "string"},
 						{Name: "Langs", Type: "[]string"},
+						{Name: "Locale", Type: "LangCtx"},
+						{Name: "Locales", Type: "[]LangCtx"},
 						{Name: "User", Type: "UserCtx"},
 					},
 				},
 				{
-					"UserCtx",
-					[]SimpleAttribute{
+					Name: "LangCtx",
+					Attributes: []SimpleAttribute{
+						{Name: "Code", Type: "string"},
+						{Name: "Name", Type: "string"},
+						{Name: "Dir", Type: "string"},
+						{Name: "Flag", Type: "string"},
+						{Name: "FlagViewBox", Type: "string"},
+					},
+				},
+				{
+					Name: "UserCtx",
+					Attributes: []SimpleAttribute{
 						{Name: "ID", Type: "string"},
 						{Name: "Role", Type: "Role"},
 						{Name: "IsActivated", Type: "bool"},
@@ -86,6 +123,8 @@ var (
 						{Name: "Lastname", Type: "string"},
 						{Name: "Email", Type: "string"},
 					},
+					From:    "User",
+					Include: []string{"IsActivated", "Firstname", "Lastname", "Email"},
 				},
 			},
 			Constructors: []ViewTypeConstructor{
@@ -95,6 +134,7 @@ var (
 					[]SimpleAttribute{
 						{Name: "tabSuffix", Type: "string"},
 						{Name: "userId", Type: "string"},
+						{Name: "lang", Type: "string"},
 					},
 				},
 				{
@@ -104,24 +144,40 @@ var (
 						{Name: "userId", Type: "string"},
 					},
 				},
+				{
+					"newLangCtx",
+					false,
+					[]SimpleAttribute{
+						{Name: "lang", Type: "string"},
+					},
+				},
 			},
 		},
 		Controllers: []Controller{
 			{Name: "View",
+				Prefix: "/",
 				IsView: true,
 				Routes: []Route{
-					{Kind: "Get", Path: "/", Target: "root"},
-					{Kind: "Get", Path: "/init", Target: "init"},
-					{Kind: "Get", Path: "/login", Target: "login"},
-					{Kind: "Get", Path: "/sign", Target: "sign"},
+					{Kind: "Get", Path: "/", Target: "root", Role: "none"},
+					{Kind: "Get", Path: "/init", Target: "init", Role: "none"},
+					{Kind: "Get", Path: "/login", Target: "login", Role: "none"},
+					{Kind: "Get", Path: "/sign", Target: "sign", Role: "none"},
 				},
 			},
 			{Name: "ApiV1",
+				Prefix: "/api/v1",
 				IsView: false,
 				Routes: []Route{
-					{Kind: "Post", Path: "/activate/admin", Target: "activateAdmin"},
-					{Kind: "Post", Path: "/login", Target: "login"},
-					{Kind: "Post", Path: "/update/lang", Target: "updateLang"},
+					{Kind: "Post", Path: "/activate/admin", Target: "activateAdmin",
+						Body: "ConnectAdminExch", Role: "none",
+						RateLimit: &RateLimit{Max: 5, Expiration: 60}},
+					{Kind: "Post", Path: "/login", Target: "login",
+						Body: "ConnectExch", Role: "none",
+						RateLimit: &RateLimit{Max: 10, Expiration: 60}},
+					{Kind: "Post", Path: "/update/lang", Target: "updateLang",
+						Body: "LangExch", Role: "none"},
+					{Kind: "Post", Path: "/i18n/reload", Target: "reloadI18n",
+						Role: "admin"},
 				},
 			},
 		},
@@ -135,6 +191,7 @@ var (
 					"ErrorUserExist",
 					"ErrorUserDisabled",
 					"ErrorInvalidUserRef",
+					"ErrorUnknownLang",
 				},
 				Methods: []Method{
 					{Name: "IsConnected",
@@ -159,29 +216,44 @@ var (
 							{Name: "ua", Type: "string"},
 						},
 						Outputs: []string{"error", "*ObjWrapper[User]"}},
+					{Name: "UpdateLang",
+						Inputs: []SimpleAttribute{
+							{Name: "session", Type: "*session.Session"},
+							{Name: "lang", Type: "string"},
+						},
+						Outputs: []string{"error"}},
 				},
 				ExchangeTypes: []VectraType[AttributeWithTag]{
 					{Name: "ConnectExch", Attributes: []AttributeWithTag{
 						{SimpleAttribute{Name: "Email", Type: "string"}, "trim,lcase", "required,email"},
-						{SimpleAttribute{Name: "Password", Type: "string"}, "", "required"},
+						{SimpleAttribute{Name: "Password", Type: "string", IsSensitive: true}, "", "required"},
 					}},
 					{Name: "ConnectAdminExch", Attributes: []AttributeWithTag{
-						{SimpleAttribute{Name: "Password", Type: "string"}, "", "required"},
+						{SimpleAttribute{Name: "Password", Type: "string", IsSensitive: true}, "", "required"},
 						{SimpleAttribute{Name: "Email", Type: "string"}, "trim,lcase", "required,email"},
 						{SimpleAttribute{Name: "Token", Type: "string"}, "", "required"},
 					}},
 					{Name: "UserExch", Attributes: []AttributeWithTag{
 						{SimpleAttribute{Name: "ID", Type: "string"}, "", ""},
-						{SimpleAttribute{Name: "Password", Type: "string"}, "trim,lcase", "required"},
+						{SimpleAttribute{Name: "Password", Type: "string", IsSensitive: true}, "", "required"},
 						{SimpleAttribute{Name: "Firstname", Type: "string"}, "trim,lcase", "required"},
 						{SimpleAttribute{Name: "Lastname", Type: "string"}, "trim,lcase", "required"},
 						{SimpleAttribute{Name: "Email", Type: "string"}, "trim,lcase", "required,email"},
-					}},
+					},
+						From:    "User",
+						Include: []string{"Firstname", "Lastname", "Email"},
+					},
 					{Name: "LangExch", Attributes: []AttributeWithTag{
 						{SimpleAttribute{Name: "Lang", Type: "string"}, "trim,lcase", "required"},
 					}},
+					{Name: "FieldErrorExch", Attributes: []AttributeWithTag{
+						{SimpleAttribute{Name: "Field", Type: "string"}, "", ""},
+						{SimpleAttribute{Name: "Tag", Type: "string"}, "", ""},
+						{SimpleAttribute{Name: "Message", Type: "string"}, "", ""},
+					}},
 					{Name: "ReasonExch", Attributes: []AttributeWithTag{
 						{SimpleAttribute{Name: "Reason", Type: "string"}, "", ""},
+						{SimpleAttribute{Name: "Fields", Type: "[]FieldErrorExch"}, "", ""},
 					}},
 				},
 			},
@@ -191,7 +263,6 @@ var (
 			{SimpleAttribute{Name: "Port", Type: "int"}, false},
 			{SimpleAttribute{Name: "IsIPv6", Type: "bool"}, false},
 			{SimpleAttribute{Name: "TabPrefix", Type: "string"}, false},
-			{SimpleAttribute{Name: "CurrentLang", Type: "string"}, true},
 			{SimpleAttribute{Name: "Roles", Type: "map[string]int"}, false},
 			{SimpleAttribute{Name: "AccessRules", Type: "[]AccessRule"}, false},
 		},
@@ -202,19 +273,25 @@ type Vectra struct {
 	generators  map[string]IGenerator `yaml:"-"`
 	ProjectPath string                `yaml:"-"`
 	isProdGen   bool                  `yaml:"-"`
+	cancelled   []string              `yaml:"-"`
 
 	WatcherConfig        `yaml:"watcher_config"`
 	SpriteConfig         `yaml:"sprite_config"`
-	NetConfProd          NetworkConfig                 `yaml:"net_conf_prod"`
-	NetConfDev           NetworkConfig                 `yaml:"net_conf_dev"`
-	ProjectName          string                        `yaml:"project_name"`
-	DefaultLang          string                        `yaml:"default_lang"`
-	WithGitignore        bool                          `yaml:"with_gitignore"`
-	WithDockerDeployment bool                          `yaml:"with_docker_deployment"`
-	WithI18nExample      bool                          `yaml:"with_i18n_example"`
-	WithSassExample      bool                          `yaml:"with_sass_example"`
-	WithPugExample       bool                          `yaml:"with_pug_example"`
-	StorageTypes         []VectraType[SimpleAttribute] `yaml:"storage_types"`
+	OpenApiConfig        `yaml:"openapi_config"`
+	JsI18nConfig         `yaml:"js_i18n_config"`
+	NetConfProd          NetworkConfig                  `yaml:"net_conf_prod"`
+	NetConfDev           NetworkConfig                  `yaml:"net_conf_dev"`
+	ProjectName          string                         `yaml:"project_name"`
+	DefaultLang          string                         `yaml:"default_lang"`
+	WithGitignore        bool                           `yaml:"with_gitignore"`
+	WithDockerDeployment bool                           `yaml:"with_docker_deployment"`
+	WithI18nExample      bool                           `yaml:"with_i18n_example"`
+	WithSassExample      bool                           `yaml:"with_sass_example"`
+	WithPugExample       bool                           `yaml:"with_pug_example"`
+	Roles                map[string]int                 `yaml:"roles"`
+	Enums                []Enum                         `yaml:"enums"`
+	ValueTypes           []VectraType[SimpleAttribute]  `yaml:"value_types"`
+	StorageTypes         []VectraType[StorageAttribute] `yaml:"storage_types"`
 	ViewTypes            `yaml:"view_types"`
 	Controllers          []Controller             `yaml:"controllers"`
 	Services             []Service                `yaml:"services"`
@@ -237,6 +314,7 @@ func NewVectra(projectPath string) *Vectra {
 	if err != nil {
 		log.Fatal("Failed to parse the project configuration file ; check syntax.")
 	}
+	vectra.addMissingDefaults()
 	if vectra.ProjectName == "" {
 		vectra.ProjectName = filepath.Base(projectPath)
 	}
@@ -254,11 +332,98 @@ func NewVectra(projectPath string) *Vectra {
 		NewTypes(&vectra),
 		NewServices(&vectra),
 		NewControllers(&vectra),
+		NewOpenApi(&vectra),
+		NewClient(&vectra),
 	)
 
 	return &vectra
 }
 
+// addMissingDefaults adds to a configuration written by a previous version the default
+// declarations the core files rely on: the types, constructors, service methods and
+// errors without declaration of the same name, and the missing attributes of User and
+// GlobalCtx and inputs of NewGlobalCtx (e.g. User.Lang, GlobalCtx.Locale and the lang
+// of NewGlobalCtx). Without roles, the default ones are declared, and the default
+// controllers get their prefix and the role of their default routes when missing.
+func (v *Vectra) addMissingDefaults() {
+
+	if len(v.Roles) == 0 {
+		v.Roles = maps.Clone(defaultVectra.Roles)
+	}
+	for i, controller := range v.Controllers {
+		j := slices.IndexFunc(defaultVectra.Controllers, func(c Controller) bool {
+			return c.Name == controller.Name
+		})
+		if j == -1 {
+			continue
+		}
+		d := defaultVectra.Controllers[j]
+		if controller.Prefix == "" {
+			v.Controllers[i].Prefix = d.Prefix
+		}
+		for k, route := range controller.Routes {
+			l := slices.IndexFunc(d.Routes, func(r Route) bool { return r.Target == route.Target })
+			if route.Role == "" && l != -1 {
+				v.Controllers[i].Routes[k].Role = d.Routes[l].Role
+			}
+		}
+	}
+
+	typeName := func(t VectraType[SimpleAttribute]) string { return t.Name }
+	attributeName := func(a SimpleAttribute) string { return a.Name }
+	constructorName := func(c ViewTypeConstructor) string { return c.Name }
+
+	v.ValueTypes = addMissing(v.ValueTypes, defaultVectra.ValueTypes, typeName)
+	v.ViewTypes.Types = addMissing(v.ViewTypes.Types, defaultVectra.ViewTypes.Types, typeName)
+	v.Constructors = addMissing(v.Constructors, defaultVectra.Constructors, constructorName)
+
+	for i, t := range v.ViewTypes.Types {
+		if t.Name == "GlobalCtx" {
+			d := named(defaultVectra.ViewTypes.Types, t.Name, typeName)
+			v.ViewTypes.Types[i].Attributes = addMissing(t.Attributes, d.Attributes, attributeName)
+		}
+	}
+	for i, c := range v.Constructors {
+		if c.Name == "NewGlobalCtx" {
+			d := named(defaultVectra.Constructors, c.Name, constructorName)
+			v.Constructors[i].Attributes = addMissing(c.Attributes, d.Attributes, attributeName)
+		}
+	}
+	for i, t := range v.StorageTypes {
+		if t.Name == "User" {
+			d := named(defaultVectra.StorageTypes, t.Name,
+				func(t VectraType[StorageAttribute]) string { return t.Name })
+			v.StorageTypes[i].Attributes = addMissing(t.Attributes, d.Attributes,
+				func(a StorageAttribute) string { return a.Name })
+		}
+	}
+	for i, service := range v.Services {
+		if service.Name == "ApiV1" {
+			d := named(defaultVectra.Services, service.Name,
+				func(s Service) string { return s.Name })
+			v.Services[i].Methods = addMissing(service.Methods, d.Methods,
+				func(m Method) string { return m.Name })
+			v.Services[i].Errors = addMissing(service.Errors, d.Errors,
+				func(e string) string { return e })
+		}
+	}
+}
+
+// named returns the item with the given name.
+func named[T any](items []T, name string, nameOf func(T) string) T {
+	return items[slices.IndexFunc(items, func(item T) bool { return nameOf(item) == name })]
+}
+
+// addMissing appends to the items the defaults whose name is not used by an item.
+func addMissing[T any](items []T, defaults []T, name func(T) string) []T {
+	for _, d := range defaults {
+		if !slices.ContainsFunc(items, func(item T) bool { return name(item) == name(d) }) {
+			items = append(items, d)
+		}
+	}
+	return items
+}
+
 func (v *Vectra) Watch() {
 
 	fmt.Println("========= Check docker =========")
@@ -348,7 +513,9 @@ func (v *Vectra) FullReport() {
 	}
 }
 
-func (v *Vectra) FullGenerate() {
+// FullGenerate runs all the generators. It returns an error when the generation of one
+// of them is cancelled.
+func (v *Vectra) FullGenerate() error {
 
 	fmt.Println("Warning: Full generation may override many files. Do you wish to continue? (yes/no)")
 	text, _ := bufio.NewReader(os.Stdin).ReadString('\n')
@@ -357,26 +524,42 @@ func (v *Vectra) FullGenerate() {
 	if response != "yes" {
 
 		fmt.Println("Full generation aborted.")
-		return
+		return nil
 	}
 
+	v.cancelled = nil
 	for _, g := range v.generators {
 		g.Generate()
 	}
 	generateSpriteSvg(v)
+
+	return v.cancellation()
 }
 
-func (v *Vectra) Generate(key string) {
+// Generate runs the generator of the given name. It returns an error when its generation
+// is cancelled.
+func (v *Vectra) Generate(key string) error {
 	if key == "sprite" {
 		generateSpriteSvg(v)
-		return
+		return nil
 	}
 	generator, ok := v.generators[key]
 	if !ok {
 		fmt.Println("The generator", key, "does not exist.")
-		return
+		return nil
 	}
+	v.cancelled = nil
 	generator.Generate()
+
+	return v.cancellation()
+}
+
+// cancellation returns the error listing the generators cancelled since the last run.
+func (v *Vectra) cancellation() error {
+	if len(v.cancelled) == 0 {
+		return nil
+	}
+	return fmt.Errorf("generation cancelled for %s", strings.Join(v.cancelled, ", "))
 }
 
 func (v *Vectra) Report(key string) {
@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestAddMissingDefaults(t *testing.T) {
+
+	v := Vectra{Controllers: []Controller{
+		{Name: "ApiV1", Routes: []Route{
+			{Kind: "Post", Path: "/login", Target: "login"},
+			{Kind: "Post", Path: "/i18n/reload", Target: "reloadI18n", Role: "registered"},
+			{Kind: "Get", Path: "/custom", Target: "custom"},
+		}},
+		{Name: "Admin", Routes: []Route{{Kind: "Get", Path: "/", Target: "root"}}},
+	}}
+	v.addMissingDefaults()
+
+	if !maps.Equal(v.Roles, defaultVectra.Roles) {
+		t.Errorf("got roles %v, want the default ones", v.Roles)
+	}
+	if prefix := v.Controllers[0].Prefix; prefix != "/api/v1" {
+		t.Errorf("got the ApiV1 prefix %q, want the default one", prefix)
+	}
+	if prefix := v.Controllers[1].Prefix; prefix != "" {
+		t.Errorf("got the Admin prefix %q, want none", prefix)
+	}
+
+	tests := []struct {
+		controller, route int
+		want              string
+	}{
+		{0, 0, "none"},
+		{0, 1, "registered"},
+		{0, 2, ""},
+		{1, 0, ""},
+	}
+	for _, test := range tests {
+		route := v.Controllers[test.controller].Routes[test.route]
+		if route.Role != test.want {
+			t.Errorf("%s: got role %q, want %q", route.Target, route.Role, test.want)
+		}
+	}
+
+	v = Vectra{Roles: map[string]int{"guest": 0}}
+	v.addMissingDefaults()
+	if !maps.Equal(v.Roles, map[string]int{"guest": 0}) {
+		t.Errorf("got roles %v, want the declared ones", v.Roles)
+	}
+}
@@ -38,8 +38,12 @@ type SassConfig struct {
 type JsConfig struct {
 	Watcher `yaml:",inline"`
 }
+
+// I18nConfig watches data/i18n to generate the translation functions again. With
+// ReloadApp, the application built for development reloads its translations too.
 type I18nConfig struct {
-	Watcher `yaml:",inline"`
+	Watcher   `yaml:",inline"`
+	ReloadApp bool `yaml:"reload_app"`
 }
 
 func IsDockerInstalled() bool {
@@ -317,7 +321,7 @@ func watchPug(v *Vectra) error {
 
 func watchJS(v *Vectra) error {
 	return WatchFiles(filepath.Join(v.ProjectPath, "static", "js"),
-		[]string{"main.js$"},
+		[]string{"(main|client|i18n).js$"},
 		[]string{"prod"},
 		200, func(pth string) {
 			_ = ExecuteCommand(
@@ -331,7 +335,7 @@ func watchJS(v *Vectra) error {
 
 func watchI18n(v *Vectra) error {
 	return WatchFiles(filepath.Join(v.ProjectPath, "data", "i18n"),
-		[]string{".*en.*\\.ini$"},
+		[]string{".*\\.(ini|ya?ml|json|po)$"},
 		[]string{},
 		200, func(pth string) {
 			v.Generate("i18n")